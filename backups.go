@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/backup"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// backupIDPattern matches the backup IDs Weaviate accepts.
+var backupIDPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// CreateBackup starts a backup of the given collections, or of every
+// collection when none are given, on the filesystem backend configured with
+// BACKUP_FILESYSTEM_PATH. It returns without waiting for the backup to finish.
+func (conn *WeaviateConnection) CreateBackup(ctx context.Context, id string, collections []string) (*models.BackupCreateResponse, error) {
+	if err := conn.checkWritable("create backup"); err != nil {
+		return nil, err
+	}
+	res, err := conn.client.Backup().Creator().
+		WithBackend(backup.BACKEND_FILESYSTEM).
+		WithBackupID(id).
+		WithIncludeClassNames(collections...).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create backup: %w", err)
+	}
+	return res, nil
+}
+
+// BackupCreateStatus returns the progress of a backup started by CreateBackup.
+func (conn *WeaviateConnection) BackupCreateStatus(ctx context.Context, id string) (*models.BackupCreateStatusResponse, error) {
+	res, err := conn.client.Backup().CreateStatusGetter().
+		WithBackend(backup.BACKEND_FILESYSTEM).
+		WithBackupID(id).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get backup status: %w", err)
+	}
+	return res, nil
+}
+
+// RestoreBackup starts restoring the given collections, or every collection
+// in the backup when none are given. The collections must not exist. It
+// returns without waiting for the restore to finish.
+func (conn *WeaviateConnection) RestoreBackup(ctx context.Context, id string, collections []string) (*models.BackupRestoreResponse, error) {
+	if err := conn.checkWritable("restore backup"); err != nil {
+		return nil, err
+	}
+	res, err := conn.client.Backup().Restorer().
+		WithBackend(backup.BACKEND_FILESYSTEM).
+		WithBackupID(id).
+		WithIncludeClassNames(collections...).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("restore backup: %w", err)
+	}
+	return res, nil
+}
+
+// BackupRestoreStatus returns the progress of a restore started by
+// RestoreBackup.
+func (conn *WeaviateConnection) BackupRestoreStatus(ctx context.Context, id string) (*models.BackupRestoreStatusResponse, error) {
+	res, err := conn.client.Backup().RestoreStatusGetter().
+		WithBackend(backup.BACKEND_FILESYSTEM).
+		WithBackupID(id).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get restore status: %w", err)
+	}
+	return res, nil
+}
+
+// backupTools returns the backup tools. Restoring recreates collections and
+// even a backup is a cluster-wide operation, so all of them are admin tools.
+func (s *MCPServer) backupTools() []server.ServerTool {
+	var tools []server.ServerTool
+
+	create := mcp.NewTool(
+		"weaviate-backup-create",
+		mcp.WithDescription("Start a backup of selected collections to the filesystem backend. Poll weaviate-backup-status until it reports SUCCESS or FAILED"),
+		mcp.WithString(
+			"backupId",
+			mcp.Description("ID of the new backup; lower case letters, digits, '-' and '_' only"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"collections",
+			mcp.Description("Collections to back up (default: all collections)"),
+			mcp.WithStringItems(),
+		),
+	)
+	tools = append(tools, s.registerTool(create, s.weaviateBackupCreate, toolAdmin)...)
+
+	status := mcp.NewTool(
+		"weaviate-backup-status",
+		mcp.WithDescription("Get the status of a backup or restore started with weaviate-backup-create or weaviate-backup-restore"),
+		mcp.WithString(
+			"backupId",
+			mcp.Description("ID of the backup"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"operation",
+			mcp.Description("Which operation to report on (default: create)"),
+			mcp.Enum("create", "restore"),
+		),
+	)
+	tools = append(tools, s.registerTool(status, s.weaviateBackupStatus, toolAdmin)...)
+
+	restore := mcp.NewTool(
+		"weaviate-backup-restore",
+		mcp.WithDescription("Start restoring collections from a filesystem backup. The collections must not exist; delete them first to roll back. Poll weaviate-backup-status with operation 'restore' until it reports SUCCESS or FAILED"),
+		mcp.WithString(
+			"backupId",
+			mcp.Description("ID of the backup to restore"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"collections",
+			mcp.Description("Collections to restore (default: all collections in the backup)"),
+			mcp.WithStringItems(),
+		),
+	)
+	tools = append(tools, s.registerTool(restore, s.weaviateBackupRestore, toolAdmin)...)
+
+	return tools
+}
+
+func (s *MCPServer) weaviateBackupCreate(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, collections, err := parseBackupArguments(req)
+	if err != nil {
+		s.logger.Error("Invalid backup arguments: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	s.logger.Debug("BackupCreate called: id=%s, collections=%v", id, collections)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	// "All collections" means all collections this server may access
+	if len(collections) == 0 && s.restrictsCollections(ctx) {
+		schema, err := conn.GetSchema(ctx)
+		if err != nil {
+			s.logger.Error("Failed to get schema: %v", err)
+			return mcp.NewToolResultErrorFromErr("failed to get schema", err), nil
+		}
+		for _, class := range s.allowedClasses(ctx, schema) {
+			collections = append(collections, class.Class)
+		}
+		if len(collections) == 0 {
+			return mcp.NewToolResultError("no collections that this server may access to back up"), nil
+		}
+	}
+	res, err := conn.CreateBackup(ctx, id, collections)
+	if err != nil {
+		s.logger.Error("BackupCreate error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to create backup", err), nil
+	}
+	// Weaviate lists every collection in the backup, including hidden ones
+	res.Classes = s.allowedCollections(ctx, res.Classes)
+	s.logger.Info("BackupCreate started: id=%s, collections=%v", id, res.Classes)
+	return backupResult(res)
+}
+
+func (s *MCPServer) weaviateBackupStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, err := req.RequireString("backupId")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	operation := req.GetString("operation", "create")
+	s.logger.Debug("BackupStatus called: id=%s, operation=%s", id, operation)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	switch operation {
+	case "create":
+		res, err := conn.BackupCreateStatus(ctx, id)
+		if err != nil {
+			s.logger.Error("BackupStatus error: %v", err)
+			return mcp.NewToolResultErrorFromErr("failed to get backup status", err), nil
+		}
+		return backupResult(res)
+	case "restore":
+		res, err := conn.BackupRestoreStatus(ctx, id)
+		if err != nil {
+			s.logger.Error("BackupStatus error: %v", err)
+			return mcp.NewToolResultErrorFromErr("failed to get restore status", err), nil
+		}
+		// Restored collections are new to the schema
+		if res.Status != nil && *res.Status == models.BackupRestoreStatusResponseStatusSUCCESS {
+			s.refreshAfterSchemaChange(ctx)
+		}
+		return backupResult(res)
+	default:
+		return mcp.NewToolResultError("'operation' must be 'create' or 'restore'"), nil
+	}
+}
+
+func (s *MCPServer) weaviateBackupRestore(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, collections, err := parseBackupArguments(req)
+	if err != nil {
+		s.logger.Error("Invalid restore arguments: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	s.logger.Debug("BackupRestore called: id=%s, collections=%v", id, collections)
+	// The backup may hold collections this server may not access
+	if len(collections) == 0 && s.restrictsCollections(ctx) {
+		return mcp.NewToolResultError("'collections' is required because this server restricts which collections may be accessed"), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	res, err := conn.RestoreBackup(ctx, id, collections)
+	if err != nil {
+		s.logger.Error("BackupRestore error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to restore backup", err), nil
+	}
+	// Weaviate lists every collection in the backup, including hidden ones
+	res.Classes = s.allowedCollections(ctx, res.Classes)
+	s.logger.Info("BackupRestore started: id=%s, collections=%v", id, res.Classes)
+	return backupResult(res)
+}
+
+// parseBackupArguments reads the backupId and collections arguments shared by
+// the create and restore tools.
+func parseBackupArguments(req mcp.CallToolRequest) (string, []string, error) {
+	id, err := req.RequireString("backupId")
+	if err != nil {
+		return "", nil, err
+	}
+	if !backupIDPattern.MatchString(id) {
+		return "", nil, fmt.Errorf("invalid backupId '%s': use lower case letters, digits, '-' and '_' only", id)
+	}
+	var collections []string
+	if _, ok := req.GetArguments()["collections"]; ok {
+		if collections, err = req.RequireStringSlice("collections"); err != nil {
+			return "", nil, err
+		}
+	}
+	return id, collections, nil
+}
+
+func backupResult(res any) (*mcp.CallToolResult, error) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal backup status", err), nil
+	}
+	return mcp.NewToolResultText(string(b)), nil
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// selfCheckTimeout bounds the startup self-check, so an unreachable Weaviate
+// delays startup by at most this long.
+const selfCheckTimeout = 5 * time.Second
+
+// ClusterStatus is the health report returned by weaviate-cluster-status.
+// Parts that could not be read are left empty and explained in Errors, so a
+// partly unhealthy cluster still produces a report.
+type ClusterStatus struct {
+	Live        bool               `json:"live"`
+	Ready       bool               `json:"ready"`
+	Version     string             `json:"version,omitempty"`
+	Modules     []string           `json:"modules,omitempty"`
+	Nodes       []NodeSummary      `json:"nodes,omitempty"`
+	Collections []CollectionShards `json:"collections,omitempty"`
+	Errors      []string           `json:"errors,omitempty"`
+}
+
+// NodeSummary is the status of one cluster node.
+type NodeSummary struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Version     string `json:"version,omitempty"`
+	ObjectCount int64  `json:"objectCount"`
+	ShardCount  int64  `json:"shardCount"`
+}
+
+// CollectionShards groups the shards of one collection across all nodes.
+type CollectionShards struct {
+	Name        string        `json:"name"`
+	ObjectCount int64         `json:"objectCount"`
+	Shards      []ShardStatus `json:"shards"`
+}
+
+// ShardStatus is the status of one shard on one node. Shards of multi-tenant
+// collections are named after their tenant.
+type ShardStatus struct {
+	Name                 string `json:"name"`
+	Node                 string `json:"node"`
+	ObjectCount          int64  `json:"objectCount"`
+	VectorIndexingStatus string `json:"vectorIndexingStatus,omitempty"`
+	Loaded               bool   `json:"loaded"`
+}
+
+// ClusterStatus collects liveness, readiness, /v1/meta and verbose node
+// status. It only fails if ctx is done; unreachable endpoints are recorded
+// in the report's Errors.
+func (conn *WeaviateConnection) ClusterStatus(ctx context.Context) (*ClusterStatus, error) {
+	status := &ClusterStatus{}
+	fail := func(part string, err error) {
+		status.Errors = append(status.Errors, fmt.Sprintf("%s: %v", part, err))
+	}
+
+	var err error
+	if status.Live, err = conn.client.Misc().LiveChecker().Do(ctx); err != nil {
+		fail("liveness", err)
+	}
+	if status.Ready, err = conn.client.Misc().ReadyChecker().Do(ctx); err != nil {
+		fail("readiness", err)
+	}
+
+	if meta, err := conn.client.Misc().MetaGetter().Do(ctx); err != nil {
+		fail("meta", err)
+	} else {
+		status.Version = meta.Version
+		if modules, ok := meta.Modules.(map[string]interface{}); ok {
+			status.Modules = sortedKeys(modules)
+		}
+	}
+
+	nodes, err := conn.client.Cluster().NodesStatusGetter().WithOutput("verbose").Do(ctx)
+	if err != nil {
+		fail("nodes", err)
+		return status, ctx.Err()
+	}
+	collections := make(map[string]*CollectionShards)
+	for _, node := range nodes.Nodes {
+		summary := NodeSummary{Name: node.Name, Version: node.Version}
+		if node.Status != nil {
+			summary.Status = *node.Status
+		}
+		if node.Stats != nil {
+			summary.ObjectCount = node.Stats.ObjectCount
+			summary.ShardCount = node.Stats.ShardCount
+		}
+		status.Nodes = append(status.Nodes, summary)
+
+		for _, shard := range node.Shards {
+			collection, ok := collections[shard.Class]
+			if !ok {
+				collection = &CollectionShards{Name: shard.Class}
+				collections[shard.Class] = collection
+			}
+			collection.ObjectCount += shard.ObjectCount
+			collection.Shards = append(collection.Shards, ShardStatus{
+				Name:                 shard.Name,
+				Node:                 node.Name,
+				ObjectCount:          shard.ObjectCount,
+				VectorIndexingStatus: shard.VectorIndexingStatus,
+				Loaded:               shard.Loaded,
+			})
+		}
+	}
+	sort.Slice(status.Nodes, func(i, j int) bool { return status.Nodes[i].Name < status.Nodes[j].Name })
+	for _, name := range sortedKeys(collections) {
+		status.Collections = append(status.Collections, *collections[name])
+	}
+	return status, ctx.Err()
+}
+
+func (s *MCPServer) weaviateClusterStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("ClusterStatus called")
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	status, err := s.clusterStatus(ctx, conn)
+	if err != nil {
+		s.logger.Error("ClusterStatus error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to get cluster status", err), nil
+	}
+	b, err := json.Marshal(status)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal cluster status", err), nil
+	}
+	s.logger.Info("ClusterStatus success: live=%v, ready=%v, nodes=%d", status.Live, status.Ready, len(status.Nodes))
+	return mcp.NewToolResultText(string(b)), nil
+}
+
+// clusterStatus returns the cluster status as far as this server may see it:
+// collections that may not be accessed are left out, and with a pinned tenant
+// so are the shards of other tenants of multi-tenant collections. The node
+// object and shard counts are then summed from the remaining shards, so they
+// reveal nothing about the hidden ones.
+func (s *MCPServer) clusterStatus(ctx context.Context, conn *WeaviateConnection) (*ClusterStatus, error) {
+	status, err := conn.ClusterStatus(ctx)
+	if err != nil {
+		return status, err
+	}
+	config := s.currentConfig()
+	if !s.restrictsCollections(ctx) && config.Tenant == "" {
+		return status, nil
+	}
+	allowed := status.Collections[:0]
+	for _, collection := range status.Collections {
+		if s.checkCollection(ctx, collection.Name) == nil {
+			allowed = append(allowed, collection)
+		}
+	}
+	status.Collections = allowed
+	if config.Tenant != "" && len(status.Collections) > 0 {
+		if err := s.pinTenantShards(ctx, conn, status); err != nil {
+			return nil, err
+		}
+	}
+	countNodeShards(status)
+	return status, nil
+}
+
+// pinTenantShards leaves only the pinned tenant's shards of the multi-tenant
+// collections in status.
+func (s *MCPServer) pinTenantShards(ctx context.Context, conn *WeaviateConnection, status *ClusterStatus) error {
+	schema, err := conn.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	tenant := s.currentConfig().Tenant
+	classes := make(map[string]*models.Class)
+	for _, class := range schema.Classes {
+		classes[class.Class] = class
+	}
+	for i := range status.Collections {
+		collection := &status.Collections[i]
+		class, ok := classes[collection.Name]
+		if !ok || s.pinnedTenant(class) == "" {
+			continue
+		}
+		shards := collection.Shards
+		collection.Shards, collection.ObjectCount = nil, 0
+		for _, shard := range shards {
+			if shard.Name == tenant {
+				collection.Shards = append(collection.Shards, shard)
+				collection.ObjectCount += shard.ObjectCount
+			}
+		}
+	}
+	return nil
+}
+
+// countNodeShards sets the object and shard counts of each node in status to
+// the totals of its shards listed in status.Collections.
+func countNodeShards(status *ClusterStatus) {
+	nodes := make(map[string]*NodeSummary, len(status.Nodes))
+	for i := range status.Nodes {
+		node := &status.Nodes[i]
+		node.ObjectCount, node.ShardCount = 0, 0
+		nodes[node.Name] = node
+	}
+	for _, collection := range status.Collections {
+		for _, shard := range collection.Shards {
+			if node, ok := nodes[shard.Node]; ok {
+				node.ObjectCount += shard.ObjectCount
+				node.ShardCount++
+			}
+		}
+	}
+}
+
+// SelfCheck logs the cluster status of every connection once at startup,
+// warning about anything that will make tool calls fail. It never stops the
+// server from starting.
+func (s *MCPServer) SelfCheck(ctx context.Context) {
+	for _, name := range s.connections.Names() {
+		conn, _ := s.connections.Get(name)
+		s.selfCheck(ctx, name, conn)
+	}
+}
+
+func (s *MCPServer) selfCheck(ctx context.Context, name string, conn *WeaviateConnection) {
+	ctx, cancel := context.WithTimeout(ctx, selfCheckTimeout)
+	defer cancel()
+	status, err := s.clusterStatus(ctx, conn)
+	if err != nil {
+		s.logger.Warn("Self-check %s failed: %v", name, err)
+		return
+	}
+	for _, msg := range status.Errors {
+		s.logger.Warn("Self-check %s: %s", name, msg)
+	}
+	if !status.Live || !status.Ready {
+		s.logger.Warn("Self-check %s: Weaviate is not healthy (live=%v, ready=%v)", name, status.Live, status.Ready)
+		return
+	}
+	s.logger.Info("Self-check %s: Weaviate %s is ready, modules: %v", name, status.Version, status.Modules)
+	for _, node := range status.Nodes {
+		if node.Status != models.NodeStatusStatusHEALTHY {
+			s.logger.Warn("Self-check %s: node %s is %s", name, node.Name, node.Status)
+		}
+	}
+	s.logger.Info("Self-check %s: %d node(s), %d collection(s)", name, len(status.Nodes), len(status.Collections))
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// collectionArguments are the tool arguments that name collections. Every
+// registered tool has them checked against the collection allow and deny
+// lists before its handler runs.
+var collectionArguments = []string{"collection", "targetCollection", "collections"}
+
+// CheckCollection returns an error explaining why collection may not be
+// accessed, or nil if it may. Patterns are globs as understood by path.Match
+// and are matched against the name as Weaviate stores it, with an upper case
+// first letter. The deny list wins over the allow list, and an empty allow
+// list allows every collection.
+func (c *Config) CheckCollection(collection string) error {
+	name := canonicalCollectionName(collection)
+	for _, pattern := range c.DeniedCollections {
+		if ok, _ := path.Match(pattern, name); ok {
+			return fmt.Errorf("access to collection '%s' is denied by this server (matches denied pattern '%s')", name, pattern)
+		}
+	}
+	if len(c.AllowedCollections) == 0 {
+		return nil
+	}
+	for _, pattern := range c.AllowedCollections {
+		if ok, _ := path.Match(pattern, name); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("access to collection '%s' is denied by this server (not in the allowed collections)", name)
+}
+
+// IsCollectionAllowed reports whether collection may be accessed.
+func (c *Config) IsCollectionAllowed(collection string) bool {
+	return c.CheckCollection(collection) == nil
+}
+
+// restrictsCollections reports whether an allow or deny list is configured.
+func (c *Config) restrictsCollections() bool {
+	return len(c.AllowedCollections) > 0 || len(c.DeniedCollections) > 0
+}
+
+// canonicalCollectionName returns collection the way Weaviate stores it:
+// class names always start with an upper case letter.
+func canonicalCollectionName(collection string) string {
+	r, size := utf8.DecodeRuneInString(collection)
+	if r == utf8.RuneError {
+		return collection
+	}
+	return string(unicode.ToUpper(r)) + collection[size:]
+}
+
+// checkCollection checks collection against the configuration in effect
+// and, for HTTP requests, against the collections the caller's token allows.
+func (s *MCPServer) checkCollection(ctx context.Context, collection string) error {
+	if err := s.currentConfig().CheckCollection(collection); err != nil {
+		return err
+	}
+	if c, ok := callerFromContext(ctx); ok && c.Collections != nil {
+		name := canonicalCollectionName(collection)
+		for _, pattern := range c.Collections {
+			if ok, _ := path.Match(pattern, name); ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("access to collection '%s' is denied for %s (not in the collections its token allows)", name, c.Name)
+	}
+	return nil
+}
+
+// restrictsCollections reports whether the configuration or the caller's
+// token limits the collections that may be accessed.
+func (s *MCPServer) restrictsCollections(ctx context.Context) bool {
+	c, ok := callerFromContext(ctx)
+	return s.currentConfig().restrictsCollections() || (ok && c.Collections != nil)
+}
+
+// allowedClasses returns the classes of schema that may be accessed.
+func (s *MCPServer) allowedClasses(ctx context.Context, schema *models.Schema) []*models.Class {
+	classes := make([]*models.Class, 0, len(schema.Classes))
+	for _, class := range schema.Classes {
+		if s.checkCollection(ctx, class.Class) == nil {
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
+
+// allowedCollections returns the collections in names that may be accessed.
+func (s *MCPServer) allowedCollections(ctx context.Context, names []string) []string {
+	allowed := make([]string, 0, len(names))
+	for _, name := range names {
+		if s.checkCollection(ctx, name) == nil {
+			allowed = append(allowed, name)
+		}
+	}
+	return allowed
+}
+
+// withCollectionCheck wraps handler so that calls naming a collection that
+// may not be accessed fail before reaching Weaviate.
+func (s *MCPServer) withCollectionCheck(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		for _, arg := range collectionArguments {
+			var names []string
+			switch value := args[arg].(type) {
+			case string:
+				names = []string{value}
+			case []interface{}:
+				for _, item := range value {
+					if name, ok := item.(string); ok {
+						names = append(names, name)
+					}
+				}
+			}
+			for _, name := range names {
+				if err := s.checkCollection(ctx, name); err != nil {
+					s.logger.Warn("Denied %s call: %v", req.Params.Name, err)
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+		}
+		return handler(ctx, req)
+	}
+}
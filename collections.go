@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// CollectionSummary is the per-collection entry returned by
+// weaviate-list-collections.
+type CollectionSummary struct {
+	// Connection is only set when listing across all connections.
+	Connection    string   `json:"connection,omitempty"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	Vectorizer    string   `json:"vectorizer,omitempty"`
+	NamedVectors  []string `json:"namedVectors,omitempty"`
+	Generative    string   `json:"generative,omitempty"`
+	MultiTenancy  bool     `json:"multiTenancy"`
+	PropertyCount int      `json:"propertyCount"`
+	// ObjectCount is nil when the count could not be read, for example for
+	// multi-tenant collections, which can only be counted per tenant.
+	ObjectCount *int64 `json:"objectCount"`
+}
+
+// ConnectionCollections is the result of weaviate-list-collections with
+// allConnections. Errors maps the name of each connection whose schema could
+// not be read to the reason, so one unreachable cluster does not hide the
+// collections of the others.
+type ConnectionCollections struct {
+	Collections []CollectionSummary `json:"collections"`
+	Errors      map[string]string   `json:"errors,omitempty"`
+}
+
+// propertySchemaDescription documents the shape of a property definition for
+// the collection management tools.
+const propertySchemaDescription = `Property definition: {"name": "title", "dataType": ["text"], ` +
+	`"description": "...", "tokenization": "word", "indexFilterable": true, "indexSearchable": true, ` +
+	`"indexRangeFilters": false, "nestedProperties": [...]}. dataType is a one-element array such as ` +
+	`["text"], ["int"], ["number"], ["boolean"], ["date"], ["uuid"], ["text[]"] or ["object"], or the ` +
+	`names of the referenced collections for a cross-reference`
+
+func (s *MCPServer) collectionTools() []server.ServerTool {
+	var tools []server.ServerTool
+
+	list := mcp.NewTool(
+		"weaviate-list-collections",
+		mcp.WithDescription("List the collections in Weaviate with their description, vectorizer, generative module, multi-tenancy flag, property count and object count"),
+		mcp.WithBoolean(
+			"allConnections",
+			mcp.Description("List the collections of every configured connection, each tagged with its connection name; connections that cannot be read are reported under errors (default: false)"),
+		),
+	)
+	tools = append(tools, s.registerTool(list, s.weaviateListCollections, toolRead)...)
+
+	dump := mcp.NewTool(
+		"weaviate-schema-dump",
+		mcp.WithDescription("Export the full live schema of every collection. The output can be applied with the schema apply command"),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format (default: json)"),
+			mcp.Enum("json", "yaml"),
+		),
+	)
+	tools = append(tools, s.registerTool(dump, s.weaviateSchemaDump, toolRead)...)
+
+	create := mcp.NewTool(
+		"weaviate-create-collection",
+		mcp.WithDescription("Create a new Weaviate collection"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the new collection; must start with an upper case letter"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"description",
+			mcp.Description("Description of the collection"),
+		),
+		mcp.WithArray(
+			"properties",
+			mcp.Description("Properties of the collection. "+propertySchemaDescription),
+			mcp.Items(map[string]any{"type": "object"}),
+		),
+		mcp.WithString(
+			"vectorizer",
+			mcp.Description("Vectorizer module, e.g. text2vec-transformers, or none to bring your own vectors (default: the server's default vectorizer)"),
+		),
+		mcp.WithObject(
+			"vectorizerConfig",
+			mcp.Description("Module configuration for the vectorizer, e.g. {\"vectorizeClassName\": false}"),
+		),
+		mcp.WithString(
+			"generative",
+			mcp.Description("Generative module, e.g. generative-ollama"),
+		),
+		mcp.WithObject(
+			"generativeConfig",
+			mcp.Description("Module configuration for the generative module, e.g. {\"model\": \"llama3\"}"),
+		),
+		mcp.WithObject(
+			"invertedIndexConfig",
+			mcp.Description("Inverted index settings, e.g. {\"indexNullState\": true, \"indexPropertyLength\": true, \"indexTimestamps\": true, \"bm25\": {\"k1\": 1.2, \"b\": 0.75}}"),
+		),
+		mcp.WithBoolean(
+			"multiTenancy",
+			mcp.Description("Enable multi-tenancy (default: false)"),
+		),
+		mcp.WithBoolean(
+			"autoTenantCreation",
+			mcp.Description("Create tenants automatically on insert; requires multiTenancy"),
+		),
+		mcp.WithBoolean(
+			"autoTenantActivation",
+			mcp.Description("Activate inactive tenants automatically on access; requires multiTenancy"),
+		),
+	)
+	tools = append(tools, s.registerTool(create, s.weaviateCreateCollection, toolAdmin)...)
+
+	addProperty := mcp.NewTool(
+		"weaviate-add-property",
+		mcp.WithDescription("Add a property to an existing Weaviate collection"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+		mcp.WithObject(
+			"property",
+			mcp.Description(propertySchemaDescription),
+			mcp.Required(),
+		),
+	)
+	tools = append(tools, s.registerTool(addProperty, s.weaviateAddProperty, toolAdmin)...)
+
+	del := mcp.NewTool(
+		"weaviate-delete-collection",
+		mcp.WithDescription("Delete a Weaviate collection and all of its objects. This cannot be undone"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the collection to delete"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"confirm",
+			mcp.Description("Repeat the collection name to confirm the deletion"),
+			mcp.Required(),
+		),
+	)
+	tools = append(tools, s.registerTool(del, s.weaviateDeleteCollection, toolAdmin)...)
+
+	return tools
+}
+
+func (s *MCPServer) weaviateListCollections(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	allConnections := req.GetBool("allConnections", false)
+	s.logger.Debug("ListCollections called: allConnections=%v", allConnections)
+	names := s.connections.Names()
+	if !allConnections {
+		name := req.GetString("connection", s.connections.PrimaryName())
+		if _, err := s.connections.Get(name); err != nil {
+			s.logger.Error("Invalid connection: %v", err)
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		names = []string{name}
+	}
+
+	summaries := []CollectionSummary{}
+	errs := make(map[string]string)
+	for _, name := range names {
+		conn, _ := s.connections.Get(name)
+		list, err := s.listCollections(ctx, conn)
+		if err != nil {
+			s.logger.Error("Failed to get schema of connection %s: %v", name, err)
+			if !allConnections {
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf("failed to get schema of connection %s", name), err), nil
+			}
+			errs[name] = err.Error()
+			continue
+		}
+		if allConnections {
+			for i := range list {
+				list[i].Connection = name
+			}
+		}
+		summaries = append(summaries, list...)
+	}
+
+	var result interface{} = summaries
+	if allConnections {
+		result = ConnectionCollections{Collections: summaries, Errors: errs}
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal collections", err), nil
+	}
+	s.logger.Info("ListCollections success: collections=%d, failed connections=%d", len(summaries), len(errs))
+	return mcp.NewToolResultText(string(b)), nil
+}
+
+// listCollections summarizes the collections of one connection, sorted by
+// name.
+func (s *MCPServer) listCollections(ctx context.Context, conn *WeaviateConnection) ([]CollectionSummary, error) {
+	schema, err := conn.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	classes := s.allowedClasses(ctx, schema)
+	summaries := make([]CollectionSummary, 0, len(classes))
+	for _, class := range classes {
+		summary := summarizeCollection(s.currentConfig().redactClass(class))
+		// Multi-tenant collections can only be counted for the pinned tenant
+		if tenant := s.pinnedTenant(class); !summary.MultiTenancy || tenant != "" {
+			if count, err := conn.CountObjects(ctx, class.Class, tenant); err != nil {
+				s.logger.Warn("Failed to count objects in %s: %v", class.Class, err)
+			} else {
+				summary.ObjectCount = &count
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// summarizeCollection extracts the schema-derived fields of a
+// CollectionSummary; the object count is filled in by the caller.
+func summarizeCollection(class *models.Class) CollectionSummary {
+	summary := CollectionSummary{
+		Name:          class.Class,
+		Description:   class.Description,
+		Vectorizer:    class.Vectorizer,
+		NamedVectors:  sortedKeys(namedVectors(class)),
+		Generative:    generativeModule(class),
+		PropertyCount: len(class.Properties),
+	}
+	if summary.Vectorizer == "none" {
+		summary.Vectorizer = ""
+	}
+	if class.MultiTenancyConfig != nil {
+		summary.MultiTenancy = class.MultiTenancyConfig.Enabled
+	}
+	return summary
+}
+
+// generativeModule returns the name of the generative-* module configured on
+// class, if any.
+func generativeModule(class *models.Class) string {
+	modules, ok := class.ModuleConfig.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, name := range sortedKeys(modules) {
+		if strings.HasPrefix(name, "generative-") {
+			return name
+		}
+	}
+	return ""
+}
+
+func (s *MCPServer) weaviateSchemaDump(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format := req.GetString("format", "json")
+	s.logger.Debug("SchemaDump called: format=%s", format)
+	if format != "json" && format != "yaml" {
+		s.logger.Error("Invalid 'format' argument: %s", format)
+		return mcp.NewToolResultError("'format' must be 'json' or 'yaml'"), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	schema, err := conn.GetSchema(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get schema: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to get schema", err), nil
+	}
+	config := s.currentConfig()
+	schema.Classes = s.allowedClasses(ctx, schema)
+	for i, class := range schema.Classes {
+		schema.Classes[i] = config.redactClass(class)
+	}
+	dump, err := dumpSchema(schema, format)
+	if err != nil {
+		s.logger.Error("SchemaDump error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to dump schema", err), nil
+	}
+	s.logger.Info("SchemaDump success: collections=%d", len(schema.Classes))
+	return mcp.NewToolResultText(dump), nil
+}
+
+func (s *MCPServer) weaviateCreateCollection(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	s.logger.Debug("CreateCollection called: args=%v", args)
+	class, err := parseCollectionDefinition(req)
+	if err != nil {
+		s.logger.Error("Invalid collection definition: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := conn.CreateCollection(ctx, class); err != nil {
+		s.logger.Error("CreateCollection error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to create collection", err), nil
+	}
+	s.refreshAfterSchemaChange(ctx)
+	s.logger.Info("CreateCollection success: collection=%s", class.Class)
+	return mcp.NewToolResultText(fmt.Sprintf("Collection '%s' created", class.Class)), nil
+}
+
+func (s *MCPServer) weaviateAddProperty(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+	s.logger.Debug("AddProperty called: args=%v", args)
+	collection, err := req.RequireString("collection")
+	if err != nil {
+		s.logger.Error("Missing 'collection' argument")
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var prop models.Property
+	if err := decodeArgument(args, "property", &prop); err != nil {
+		s.logger.Error("Invalid 'property' argument: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := validatePropertyDefinition(&prop); err != nil {
+		s.logger.Error("Invalid property definition: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := conn.AddProperty(ctx, collection, &prop); err != nil {
+		s.logger.Error("AddProperty error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to add property", err), nil
+	}
+	s.logger.Info("AddProperty success: collection=%s, property=%s", collection, prop.Name)
+	return mcp.NewToolResultText(fmt.Sprintf("Property '%s' added to collection '%s'", prop.Name, collection)), nil
+}
+
+func (s *MCPServer) weaviateDeleteCollection(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.logger.Debug("DeleteCollection called: args=%v", req.GetArguments())
+	collection, err := req.RequireString("collection")
+	if err != nil {
+		s.logger.Error("Missing 'collection' argument")
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if confirm := req.GetString("confirm", ""); confirm != collection {
+		s.logger.Error("DeleteCollection not confirmed: collection=%s, confirm=%s", collection, confirm)
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"deletion not confirmed: 'confirm' must repeat the collection name '%s'", collection)), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := conn.DeleteCollection(ctx, collection); err != nil {
+		s.logger.Error("DeleteCollection error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to delete collection", err), nil
+	}
+	s.refreshAfterSchemaChange(ctx)
+	s.logger.Info("DeleteCollection success: collection=%s", collection)
+	return mcp.NewToolResultText(fmt.Sprintf("Collection '%s' deleted", collection)), nil
+}
+
+// refreshAfterSchemaChange updates the per-collection resources right away
+// after a tool created or deleted a collection.
+func (s *MCPServer) refreshAfterSchemaChange(ctx context.Context) {
+	if err := s.RefreshResources(ctx); err != nil {
+		s.logger.Warn("Failed to refresh schema resources: %v", err)
+	}
+}
+
+// parseCollectionDefinition builds a class definition from the arguments of
+// weaviate-create-collection.
+func parseCollectionDefinition(req mcp.CallToolRequest) (*models.Class, error) {
+	args := req.GetArguments()
+	name, err := req.RequireString("collection")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, fmt.Errorf("'collection' must not be empty")
+	}
+	if r := []rune(name); !unicode.IsUpper(r[0]) {
+		return nil, fmt.Errorf("collection name '%s' must start with an upper case letter", name)
+	}
+	class := &models.Class{
+		Class:       name,
+		Description: req.GetString("description", ""),
+		Vectorizer:  req.GetString("vectorizer", ""),
+	}
+	if err := decodeArgument(args, "properties", &class.Properties); err != nil {
+		return nil, err
+	}
+	for _, prop := range class.Properties {
+		if err := validatePropertyDefinition(prop); err != nil {
+			return nil, err
+		}
+	}
+
+	moduleConfig := make(map[string]interface{})
+	if class.Vectorizer != "" && class.Vectorizer != "none" {
+		vectorizerConfig := map[string]interface{}{}
+		if err := decodeArgument(args, "vectorizerConfig", &vectorizerConfig); err != nil {
+			return nil, err
+		}
+		moduleConfig[class.Vectorizer] = vectorizerConfig
+	} else if _, ok := args["vectorizerConfig"]; ok {
+		return nil, fmt.Errorf("'vectorizerConfig' requires a 'vectorizer'")
+	}
+	if generative := req.GetString("generative", ""); generative != "" {
+		if !strings.HasPrefix(generative, "generative-") {
+			return nil, fmt.Errorf("invalid generative module '%s', expected a generative-* module", generative)
+		}
+		generativeConfig := map[string]interface{}{}
+		if err := decodeArgument(args, "generativeConfig", &generativeConfig); err != nil {
+			return nil, err
+		}
+		moduleConfig[generative] = generativeConfig
+	} else if _, ok := args["generativeConfig"]; ok {
+		return nil, fmt.Errorf("'generativeConfig' requires a 'generative' module")
+	}
+	if len(moduleConfig) > 0 {
+		class.ModuleConfig = moduleConfig
+	}
+
+	if _, ok := args["invertedIndexConfig"]; ok {
+		class.InvertedIndexConfig = &models.InvertedIndexConfig{}
+		if err := decodeArgument(args, "invertedIndexConfig", class.InvertedIndexConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	multiTenancy := req.GetBool("multiTenancy", false)
+	autoCreation := req.GetBool("autoTenantCreation", false)
+	autoActivation := req.GetBool("autoTenantActivation", false)
+	if !multiTenancy && (autoCreation || autoActivation) {
+		return nil, fmt.Errorf("'autoTenantCreation' and 'autoTenantActivation' require 'multiTenancy'")
+	}
+	if multiTenancy {
+		class.MultiTenancyConfig = &models.MultiTenancyConfig{
+			Enabled:              true,
+			AutoTenantCreation:   autoCreation,
+			AutoTenantActivation: autoActivation,
+		}
+	}
+	return class, nil
+}
+
+func validatePropertyDefinition(prop *models.Property) error {
+	if prop == nil || prop.Name == "" {
+		return fmt.Errorf("every property needs a 'name'")
+	}
+	if len(prop.DataType) == 0 {
+		return fmt.Errorf("property '%s' needs a 'dataType', e.g. [\"text\"]", prop.Name)
+	}
+	return nil
+}
+
+// decodeArgument converts the JSON-decoded tool argument key into target.
+// A missing argument leaves target untouched.
+func decodeArgument(args map[string]any, key string, target any) error {
+	raw, ok := args[key]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("invalid '%s' argument: %w", key, err)
+	}
+	if err := json.Unmarshal(b, target); err != nil {
+		return fmt.Errorf("invalid '%s' argument: %w", key, err)
+	}
+	return nil
+}
@@ -3,83 +3,401 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// Config holds all configuration for the MCP server
+// Config holds all configuration for the MCP server. The yaml and toml tags
+// name the keys of the config file; fields tagged secret are redacted by
+// "config print".
 type Config struct {
-	// Weaviate connection
-	WeaviateHost   string
-	WeaviateScheme string
+	// ConfigFile is the file the configuration was loaded from, if any
+	ConfigFile string `yaml:"-" toml:"-"`
+
+	// Weaviate connection, used when Connections is empty
+	WeaviateHost     string `yaml:"weaviate_host" toml:"weaviate_host"`
+	WeaviateScheme   string `yaml:"weaviate_scheme" toml:"weaviate_scheme"`
+	WeaviateAPIKey   string `yaml:"weaviate_api_key" toml:"weaviate_api_key" secret:"true"`
+	ConsistencyLevel string `yaml:"consistency_level" toml:"consistency_level"` // default for reads and writes; empty leaves it to Weaviate
+
+	// Named Weaviate connections, selected per call with the connection
+	// argument. Calls without one use PrimaryConnection, which may be
+	// omitted when there is only one connection.
+	Connections       map[string]ConnectionConfig `yaml:"connections" toml:"connections"`
+	PrimaryConnection string                      `yaml:"primary_connection" toml:"primary_connection"`
 
 	// Server configuration
-	Transport string // "stdio" or "http"
-	HTTPPort  int
-	HTTPHost  string
+	Transport string `yaml:"transport" toml:"transport"` // "stdio" or "http"
+	HTTPPort  int    `yaml:"http_port" toml:"http_port"`
+	HTTPHost  string `yaml:"http_host" toml:"http_host"`
+
+	// TLS for the HTTP transport; a client CA file also requires clients to
+	// present a certificate signed by one of its CAs
+	HTTPTLSCertFile     string `yaml:"http_tls_cert_file" toml:"http_tls_cert_file"`
+	HTTPTLSKeyFile      string `yaml:"http_tls_key_file" toml:"http_tls_key_file"`
+	HTTPTLSClientCAFile string `yaml:"http_tls_client_ca_file" toml:"http_tls_client_ca_file"`
+
+	// Origins allowed to call the HTTP transport from a browser, e.g.
+	// https://app.example.com, or "*" for any
+	HTTPCORSAllowedOrigins []string `yaml:"http_cors_allowed_origins" toml:"http_cors_allowed_origins"`
+
+	// HTTPAPIKeys authenticate clients of the HTTP transport; see
+	// authenticate. Required unless HTTPHost is a loopback address.
+	HTTPAPIKeys []APIKey `yaml:"http_api_keys" toml:"http_api_keys"`
+
+	// OAuth makes the HTTP transport an OAuth 2.1 resource server that
+	// accepts JWT access tokens; it is enabled by setting an issuer.
+	OAuth OAuthConfig `yaml:"oauth" toml:"oauth"`
 
 	// Logging
-	LogLevel  string // "debug", "info", "warn", "error"
-	LogOutput string // "stderr", "file", or "both"
+	LogLevel  string `yaml:"log_level" toml:"log_level"`   // "debug", "info", "warn", "error"
+	LogOutput string `yaml:"log_output" toml:"log_output"` // "stderr", "file", or "both"
 
 	// Security
-	ReadOnly      bool
-	DisabledTools []string
+	ReadOnly         bool     `yaml:"read_only" toml:"read_only"`
+	EnableAdminTools bool     `yaml:"enable_admin_tools" toml:"enable_admin_tools"` // collection management tools; ignored when ReadOnly
+	DisabledTools    []string `yaml:"disabled_tools" toml:"disabled_tools"`
+
+	// Collection names or glob patterns; see CheckCollection
+	AllowedCollections []string `yaml:"allowed_collections" toml:"allowed_collections"`
+	DeniedCollections  []string `yaml:"denied_collections" toml:"denied_collections"`
+
+	// Limits caps tool arguments; PerToolLimits overrides them per tool name
+	Limits        ToolLimits            `yaml:"limits" toml:"limits"`
+	PerToolLimits map[string]ToolLimits `yaml:"tool_limits" toml:"tool_limits"`
+
+	// Redaction holds the property redaction policy per collection name or
+	// glob pattern. It is compiled into redactions by Validate.
+	Redaction  map[string]RedactionPolicy `yaml:"redaction" toml:"redaction"`
+	redactions []compiledRedaction
+
+	// Resources
+	SchemaRefreshInterval    time.Duration `yaml:"schema_refresh_interval" toml:"schema_refresh_interval"`       // 0 disables polling for schema changes
+	SubscriptionPollInterval time.Duration `yaml:"subscription_poll_interval" toml:"subscription_poll_interval"` // 0 disables resources/updated notifications
+	ConfigWatchInterval      time.Duration `yaml:"config_watch_interval" toml:"config_watch_interval"`           // 0 disables reloading on config file changes
+
+	// Multi-tenancy
+	Tenant string `yaml:"tenant" toml:"tenant"` // pins every call on multi-tenant collections to this tenant
 
 	// Other
-	DefaultCollection string
+	DefaultCollection string `yaml:"default_collection" toml:"default_collection"`
+}
+
+// ConnectionConfig holds the settings of one named Weaviate connection.
+type ConnectionConfig struct {
+	Host             string            `yaml:"host" toml:"host"`
+	Scheme           string            `yaml:"scheme" toml:"scheme"` // defaults to "http"
+	APIKey           string            `yaml:"api_key" toml:"api_key" secret:"true"`
+	Headers          map[string]string `yaml:"headers" toml:"headers" secret:"true"` // e.g. X-OpenAI-Api-Key
+	ConsistencyLevel string            `yaml:"consistency_level" toml:"consistency_level"`
+	ReadOnly         bool              `yaml:"read_only" toml:"read_only"` // also implied by the top-level ReadOnly
+}
+
+// RedactionPolicy hides or masks the properties of a collection in
+// everything the server returns.
+type RedactionPolicy struct {
+	Hide []string   `yaml:"hide" toml:"hide"` // properties left out of schemas and results
+	Mask []MaskRule `yaml:"mask" toml:"mask"`
+}
+
+// MaskRule replaces the matches of Pattern in every string value of Property
+// with Replacement, which may refer to capture groups as $1.
+type MaskRule struct {
+	Property    string `yaml:"property" toml:"property"`
+	Pattern     string `yaml:"pattern" toml:"pattern"`
+	Replacement string `yaml:"replacement" toml:"replacement"`
+}
+
+// APIKey is a static key, sent as a bearer token or in the X-API-Key header,
+// that lets a client use the HTTP transport. Scope is "read", "write" or
+// "admin" and decides which tools the client can see and call.
+type APIKey struct {
+	Name  string `yaml:"name" toml:"name"` // identifies the client in logs
+	Key   string `yaml:"key" toml:"key" secret:"true"`
+	Scope string `yaml:"scope" toml:"scope"`
+}
+
+// OAuthConfig describes the authorization server whose access tokens the
+// HTTP transport accepts and how their claims map to scopes and collections.
+type OAuthConfig struct {
+	Issuer   string `yaml:"issuer" toml:"issuer"`     // expected iss claim
+	Resource string `yaml:"resource" toml:"resource"` // public URL of the MCP endpoint, e.g. https://mcp.example.com/mcp
+	Audience string `yaml:"audience" toml:"audience"` // expected aud claim; defaults to Resource
+	JWKSURL  string `yaml:"jwks_url" toml:"jwks_url"` // where the issuer publishes its signing keys
+
+	// ScopeClaim names the claim holding the token's scopes, as a
+	// space-separated string or a list (default "scope"). Scopes maps token
+	// scopes to "read", "write" or "admin" (default: those names map to
+	// themselves); the highest one granted applies.
+	ScopeClaim string            `yaml:"scope_claim" toml:"scope_claim"`
+	Scopes     map[string]string `yaml:"scopes" toml:"scopes"`
+
+	// CollectionsClaim names an optional claim listing the collection names
+	// or glob patterns the token may access, on top of the server's lists.
+	CollectionsClaim string `yaml:"collections_claim" toml:"collections_claim"`
+}
+
+// defaultConnectionName names the connection built from the top-level
+// Weaviate settings when no Connections are configured.
+const defaultConnectionName = "default"
+
+// connectionConfigs returns every configured connection by name.
+func (c *Config) connectionConfigs() map[string]ConnectionConfig {
+	if len(c.Connections) == 0 {
+		return map[string]ConnectionConfig{defaultConnectionName: {
+			Host:             c.WeaviateHost,
+			Scheme:           c.WeaviateScheme,
+			APIKey:           c.WeaviateAPIKey,
+			ConsistencyLevel: c.ConsistencyLevel,
+			ReadOnly:         c.ReadOnly,
+		}}
+	}
+	conns := make(map[string]ConnectionConfig, len(c.Connections))
+	for name, conn := range c.Connections {
+		if conn.Scheme == "" {
+			conn.Scheme = "http"
+		}
+		conn.ReadOnly = conn.ReadOnly || c.ReadOnly
+		conns[name] = conn
+	}
+	return conns
+}
+
+// primaryConnection returns the name of the connection used by calls that
+// do not select one.
+func (c *Config) primaryConnection() string {
+	if c.PrimaryConnection != "" {
+		return c.PrimaryConnection
+	}
+	if len(c.Connections) == 1 {
+		for name := range c.Connections {
+			return name
+		}
+	}
+	return defaultConnectionName
+}
+
+// defaultConfig returns the configuration used when nothing is set.
+func defaultConfig() *Config {
+	return &Config{
+		WeaviateHost:             "host.docker.internal:8080",
+		WeaviateScheme:           "http",
+		Transport:                "stdio",
+		HTTPPort:                 3000,
+		HTTPHost:                 "127.0.0.1",
+		LogLevel:                 "info",
+		LogOutput:                "stderr",
+		DefaultCollection:        "DefaultCollection",
+		SchemaRefreshInterval:    30 * time.Second,
+		SubscriptionPollInterval: 10 * time.Second,
+		ConfigWatchInterval:      5 * time.Second,
+		Limits: ToolLimits{
+			MaxLimit:            100,
+			MaxTargetProperties: 50,
+			MaxQueryLength:      2000,
+			MaxFilterNodes:      50,
+			MaxBatchSize:        100,
+		},
+	}
 }
 
-// LoadConfig loads configuration from environment variables and command-line flags
+// LoadConfig loads configuration from the defaults, the config file,
+// environment variables and command-line flags, each overriding the ones
+// before it.
 func LoadConfig() (*Config, error) {
-	config := &Config{
-		// Defaults
-		WeaviateHost:      getEnvOrDefault("WEAVIATE_HOST", "host.docker.internal:8080"),
-		WeaviateScheme:    getEnvOrDefault("WEAVIATE_SCHEME", "http"),
-		Transport:         getEnvOrDefault("MCP_TRANSPORT", "stdio"),
-		HTTPPort:          3000,
-		HTTPHost:          "127.0.0.1",
-		LogLevel:          getEnvOrDefault("MCP_LOG_LEVEL", "info"),
-		LogOutput:         getEnvOrDefault("MCP_LOG_OUTPUT", "stderr"),
-		ReadOnly:          getEnvBool("MCP_READ_ONLY"),
-		DefaultCollection: getEnvOrDefault("MCP_DEFAULT_COLLECTION", "DefaultCollection"),
+	config, err := parseConfig(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return config, nil
+}
+
+// ReloadConfig loads the configuration again like LoadConfig, picking up
+// changes to the config file. The command line is parsed again too, so flags
+// keep overriding the file. The result is not validated: the caller restores
+// the settings that only apply at startup first and then calls Validate.
+func ReloadConfig() (*Config, error) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return parseConfig(fs, os.Args[1:])
+}
+
+// parseConfig loads the configuration like LoadConfig, registering the
+// command-line flags on fs and parsing them from args, without validating it.
+func parseConfig(fs *flag.FlagSet, args []string) (*Config, error) {
+	config := defaultConfig()
+
+	// The file is loaded before the flags are parsed, so --config is picked
+	// out of args by hand
+	config.ConfigFile = os.Getenv("MCP_CONFIG_FILE")
+	if path := configFileFromArgs(args); path != "" {
+		config.ConfigFile = path
+	}
+	if config.ConfigFile != "" {
+		if err := config.loadFile(config.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
+	config.loadEnv()
+
+	// Command-line flags (override environment variables)
+	fs.StringVar(&config.ConfigFile, "config", config.ConfigFile, "Config file (YAML or TOML)")
+	fs.StringVar(&config.WeaviateHost, "weaviate-host", config.WeaviateHost, "Weaviate host")
+	fs.StringVar(&config.WeaviateScheme, "weaviate-scheme", config.WeaviateScheme, "Weaviate scheme (http/https)")
+	fs.StringVar(&config.ConsistencyLevel, "consistency-level", config.ConsistencyLevel, "Default consistency level for reads and writes (ONE/QUORUM/ALL)")
+	fs.StringVar(&config.PrimaryConnection, "primary-connection", config.PrimaryConnection, "Named connection used by calls that do not select one")
+	fs.StringVar(&config.Transport, "transport", config.Transport, "Transport protocol (stdio/http)")
+	fs.IntVar(&config.HTTPPort, "http-port", config.HTTPPort, "HTTP port when using http transport")
+	fs.StringVar(&config.HTTPHost, "http-host", config.HTTPHost, "HTTP host when using http transport")
+	fs.StringVar(&config.HTTPTLSCertFile, "http-tls-cert-file", config.HTTPTLSCertFile, "TLS certificate file for the HTTP transport")
+	fs.StringVar(&config.HTTPTLSKeyFile, "http-tls-key-file", config.HTTPTLSKeyFile, "TLS private key file for the HTTP transport")
+	fs.StringVar(&config.HTTPTLSClientCAFile, "http-tls-client-ca-file", config.HTTPTLSClientCAFile, "CA certificates that HTTP clients must present a certificate from (enables mTLS)")
+	fs.Func("http-cors-allowed-origins", "Comma-separated origins allowed to call the HTTP transport from a browser, or *", func(value string) error {
+		config.HTTPCORSAllowedOrigins = splitList(value)
+		return nil
+	})
+	fs.StringVar(&config.OAuth.Issuer, "oauth-issuer", config.OAuth.Issuer, "OAuth issuer whose access tokens the HTTP transport accepts")
+	fs.StringVar(&config.OAuth.Resource, "oauth-resource", config.OAuth.Resource, "Public URL of the MCP endpoint, advertised as the OAuth protected resource")
+	fs.StringVar(&config.OAuth.Audience, "oauth-audience", config.OAuth.Audience, "Expected audience of access tokens (default: the resource URL)")
+	fs.StringVar(&config.OAuth.JWKSURL, "oauth-jwks-url", config.OAuth.JWKSURL, "URL of the issuer's JSON Web Key Set")
+	fs.StringVar(&config.LogLevel, "log-level", config.LogLevel, "Log level (debug/info/warn/error)")
+	fs.StringVar(&config.LogOutput, "log-output", config.LogOutput, "Log output (stderr/file/both)")
+	fs.BoolVar(&config.ReadOnly, "read-only", config.ReadOnly, "Enable read-only mode")
+	fs.BoolVar(&config.EnableAdminTools, "enable-admin-tools", config.EnableAdminTools, "Enable collection management tools (ignored in read-only mode)")
+	fs.Func("disabled-tools", "Comma-separated list of tools to disable", func(value string) error {
+		config.DisabledTools = splitList(value)
+		return nil
+	})
+	fs.Func("allowed-collections", "Comma-separated collection names or glob patterns that may be accessed (default: all)", func(value string) error {
+		config.AllowedCollections = splitList(value)
+		return nil
+	})
+	fs.Func("denied-collections", "Comma-separated collection names or glob patterns that may not be accessed", func(value string) error {
+		config.DeniedCollections = splitList(value)
+		return nil
+	})
+	fs.IntVar(&config.Limits.MaxLimit, "max-limit", config.Limits.MaxLimit, "Maximum 'limit' of a tool call (0 for no limit)")
+	fs.IntVar(&config.Limits.MaxTargetProperties, "max-target-properties", config.Limits.MaxTargetProperties, "Maximum number of targetProperties (0 for no limit)")
+	fs.IntVar(&config.Limits.MaxQueryLength, "max-query-length", config.Limits.MaxQueryLength, "Maximum query length in characters (0 for no limit)")
+	fs.IntVar(&config.Limits.MaxFilterNodes, "max-filter-nodes", config.Limits.MaxFilterNodes, "Maximum number of operators in a filter (0 for no limit)")
+	fs.IntVar(&config.Limits.MaxBatchSize, "max-batch-size", config.Limits.MaxBatchSize, "Maximum number of objects inserted per call (0 for no limit)")
+	fs.StringVar(&config.DefaultCollection, "default-collection", config.DefaultCollection, "Default collection name")
+	fs.StringVar(&config.Tenant, "tenant", config.Tenant, "Pin all calls on multi-tenant collections to this tenant")
+	fs.DurationVar(&config.SchemaRefreshInterval, "schema-refresh-interval", config.SchemaRefreshInterval, "How often to poll Weaviate for new or removed collections (0 disables)")
+	fs.DurationVar(&config.SubscriptionPollInterval, "subscription-poll-interval", config.SubscriptionPollInterval, "How often to poll subscribed resources for changes (0 disables)")
+	fs.DurationVar(&config.ConfigWatchInterval, "config-watch-interval", config.ConfigWatchInterval, "How often to check the config file for changes (0 disables)")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadEnv overrides the configuration with the environment variables that
+// are set.
+func (c *Config) loadEnv() {
+	c.WeaviateHost = getEnvOrDefault("WEAVIATE_HOST", c.WeaviateHost)
+	c.WeaviateScheme = getEnvOrDefault("WEAVIATE_SCHEME", c.WeaviateScheme)
+	c.WeaviateAPIKey = getEnvOrDefault("WEAVIATE_API_KEY", c.WeaviateAPIKey)
+	c.PrimaryConnection = getEnvOrDefault("MCP_PRIMARY_CONNECTION", c.PrimaryConnection)
+	c.ConsistencyLevel = getEnvOrDefault("WEAVIATE_CONSISTENCY_LEVEL", c.ConsistencyLevel)
+	c.Transport = getEnvOrDefault("MCP_TRANSPORT", c.Transport)
+	c.HTTPHost = getEnvOrDefault("MCP_HTTP_HOST", c.HTTPHost)
+	c.HTTPTLSCertFile = getEnvOrDefault("MCP_HTTP_TLS_CERT_FILE", c.HTTPTLSCertFile)
+	c.HTTPTLSKeyFile = getEnvOrDefault("MCP_HTTP_TLS_KEY_FILE", c.HTTPTLSKeyFile)
+	c.HTTPTLSClientCAFile = getEnvOrDefault("MCP_HTTP_TLS_CLIENT_CA_FILE", c.HTTPTLSClientCAFile)
+	c.OAuth.Issuer = getEnvOrDefault("MCP_OAUTH_ISSUER", c.OAuth.Issuer)
+	c.OAuth.Resource = getEnvOrDefault("MCP_OAUTH_RESOURCE", c.OAuth.Resource)
+	c.OAuth.Audience = getEnvOrDefault("MCP_OAUTH_AUDIENCE", c.OAuth.Audience)
+	c.OAuth.JWKSURL = getEnvOrDefault("MCP_OAUTH_JWKS_URL", c.OAuth.JWKSURL)
+	c.LogLevel = getEnvOrDefault("MCP_LOG_LEVEL", c.LogLevel)
+	c.LogOutput = getEnvOrDefault("MCP_LOG_OUTPUT", c.LogOutput)
+	c.ReadOnly = getEnvBoolOrDefault("MCP_READ_ONLY", c.ReadOnly)
+	c.EnableAdminTools = getEnvBoolOrDefault("MCP_ENABLE_ADMIN_TOOLS", c.EnableAdminTools)
+	c.DefaultCollection = getEnvOrDefault("MCP_DEFAULT_COLLECTION", c.DefaultCollection)
+	c.Tenant = getEnvOrDefault("MCP_TENANT", c.Tenant)
+
+	// Parse schema refresh interval
+	if intervalStr := os.Getenv("MCP_SCHEMA_REFRESH_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			c.SchemaRefreshInterval = interval
+		}
+	}
+
+	// Parse subscription poll interval
+	if intervalStr := os.Getenv("MCP_SUBSCRIPTION_POLL_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			c.SubscriptionPollInterval = interval
+		}
+	}
+
+	// Parse config watch interval
+	if intervalStr := os.Getenv("MCP_CONFIG_WATCH_INTERVAL"); intervalStr != "" {
+		if interval, err := time.ParseDuration(intervalStr); err == nil {
+			c.ConfigWatchInterval = interval
+		}
 	}
 
 	// Parse HTTP port
 	if portStr := os.Getenv("MCP_HTTP_PORT"); portStr != "" {
 		if port, err := strconv.Atoi(portStr); err == nil {
-			config.HTTPPort = port
+			c.HTTPPort = port
+		}
+	}
+
+	// Parse tool limits
+	for key, limit := range map[string]*int{
+		"MCP_MAX_LIMIT":             &c.Limits.MaxLimit,
+		"MCP_MAX_TARGET_PROPERTIES": &c.Limits.MaxTargetProperties,
+		"MCP_MAX_QUERY_LENGTH":      &c.Limits.MaxQueryLength,
+		"MCP_MAX_FILTER_NODES":      &c.Limits.MaxFilterNodes,
+		"MCP_MAX_BATCH_SIZE":        &c.Limits.MaxBatchSize,
+	} {
+		if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+			*limit = value
 		}
 	}
 
 	// Parse disabled tools
 	if disabled := os.Getenv("MCP_DISABLED_TOOLS"); disabled != "" {
-		config.DisabledTools = strings.Split(disabled, ",")
-		for i, tool := range config.DisabledTools {
-			config.DisabledTools[i] = strings.TrimSpace(tool)
-		}
+		c.DisabledTools = splitList(disabled)
 	}
 
-	// Command-line flags (override environment variables)
-	flag.StringVar(&config.WeaviateHost, "weaviate-host", config.WeaviateHost, "Weaviate host")
-	flag.StringVar(&config.WeaviateScheme, "weaviate-scheme", config.WeaviateScheme, "Weaviate scheme (http/https)")
-	flag.StringVar(&config.Transport, "transport", config.Transport, "Transport protocol (stdio/http)")
-	flag.IntVar(&config.HTTPPort, "http-port", config.HTTPPort, "HTTP port when using http transport")
-	flag.StringVar(&config.HTTPHost, "http-host", config.HTTPHost, "HTTP host when using http transport")
-	flag.StringVar(&config.LogLevel, "log-level", config.LogLevel, "Log level (debug/info/warn/error)")
-	flag.StringVar(&config.LogOutput, "log-output", config.LogOutput, "Log output (stderr/file/both)")
-	flag.BoolVar(&config.ReadOnly, "read-only", config.ReadOnly, "Enable read-only mode")
-	flag.StringVar(&config.DefaultCollection, "default-collection", config.DefaultCollection, "Default collection name")
-
-	flag.Parse()
-
-	// Validate configuration
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+	// Parse CORS allowed origins
+	if origins := os.Getenv("MCP_HTTP_CORS_ALLOWED_ORIGINS"); origins != "" {
+		c.HTTPCORSAllowedOrigins = splitList(origins)
 	}
 
-	return config, nil
+	// Parse HTTP API keys, given as name:scope:key
+	if keys := os.Getenv("MCP_HTTP_API_KEYS"); keys != "" {
+		c.HTTPAPIKeys = nil
+		for _, entry := range splitList(keys) {
+			var key APIKey
+			parts := strings.SplitN(entry, ":", 3)
+			if len(parts) == 3 {
+				key = APIKey{Name: parts[0], Scope: parts[1], Key: parts[2]}
+			}
+			c.HTTPAPIKeys = append(c.HTTPAPIKeys, key)
+		}
+	}
+
+	// Parse collection allow and deny lists
+	if allowed := os.Getenv("MCP_ALLOWED_COLLECTIONS"); allowed != "" {
+		c.AllowedCollections = splitList(allowed)
+	}
+	if denied := os.Getenv("MCP_DENIED_COLLECTIONS"); denied != "" {
+		c.DeniedCollections = splitList(denied)
+	}
 }
 
 // Validate checks if the configuration is valid
@@ -98,6 +416,70 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid log output: %s", c.LogOutput)
 	}
 
+	if err := c.validateAPIKeys(); err != nil {
+		return err
+	}
+	if err := c.OAuth.validate(); err != nil {
+		return err
+	}
+	if err := c.validateHTTPServer(); err != nil {
+		return err
+	}
+	if c.Transport == "http" && len(c.HTTPAPIKeys) == 0 && !c.OAuth.enabled() && c.HTTPTLSClientCAFile == "" && !isLoopbackHost(c.HTTPHost) {
+		return fmt.Errorf("http transport on %s requires http_api_keys, oauth or a TLS client CA; bind to a loopback address to run without authentication", c.HTTPHost)
+	}
+
+	conns := c.connectionConfigs()
+	for _, name := range sortedKeys(conns) {
+		conn := conns[name]
+		if conn.Host == "" {
+			return fmt.Errorf("connection %s: host is required", name)
+		}
+		if conn.Scheme != "http" && conn.Scheme != "https" {
+			return fmt.Errorf("connection %s: invalid scheme: %s, must be 'http' or 'https'", name, conn.Scheme)
+		}
+		if conn.ConsistencyLevel != "" && !slices.Contains(consistencyLevels, conn.ConsistencyLevel) {
+			return fmt.Errorf("connection %s: invalid consistency level: %s, must be one of %s", name, conn.ConsistencyLevel, strings.Join(consistencyLevels, ", "))
+		}
+	}
+	if _, ok := conns[c.primaryConnection()]; !ok {
+		if c.PrimaryConnection == "" {
+			return fmt.Errorf("primary connection must be set when more than one connection is configured")
+		}
+		return fmt.Errorf("primary connection %s is not configured", c.PrimaryConnection)
+	}
+
+	for _, pattern := range slices.Concat(c.AllowedCollections, c.DeniedCollections) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid collection pattern: %s", pattern)
+		}
+	}
+
+	if err := c.Limits.validate(); err != nil {
+		return err
+	}
+	for _, tool := range sortedKeys(c.PerToolLimits) {
+		if err := c.PerToolLimits[tool].validate(); err != nil {
+			return fmt.Errorf("tool_limits %s: %w", tool, err)
+		}
+	}
+
+	if err := c.compileRedaction(); err != nil {
+		return err
+	}
+
+	if c.SchemaRefreshInterval < 0 {
+		return fmt.Errorf("invalid schema refresh interval: %s", c.SchemaRefreshInterval)
+	}
+
+	if c.SubscriptionPollInterval < 0 {
+		return fmt.Errorf("invalid subscription poll interval: %s", c.SubscriptionPollInterval)
+	}
+
+	if c.ConfigWatchInterval < 0 {
+		return fmt.Errorf("invalid config watch interval: %s", c.ConfigWatchInterval)
+	}
+
 	return nil
 }
 
@@ -119,7 +501,19 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvBool(key string) bool {
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
 	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
 	return value == "true" || value == "1" || value == "yes"
 }
+
+// splitList splits a comma-separated list, trimming spaces around items.
+func splitList(value string) []string {
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in "config print" output.
+const redacted = "REDACTED"
+
+// configFileFromArgs returns the value of a --config flag in args, looking
+// only at the flags before the first non-flag argument as flag.Parse does.
+func configFileFromArgs(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return ""
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// loadFile overrides the configuration with the settings of a YAML or TOML
+// file, chosen by extension. Unknown keys are an error, so typos do not go
+// unnoticed.
+func (c *Config) loadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && err != io.EOF {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(b), c)
+		if err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse config file %s: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported extension, use .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// runConfigCommand runs the "config" CLI subcommands and returns the process
+// exit code.
+func runConfigCommand(args []string, config *Config) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: mcp-server-weaviate config print [-format yaml|toml]")
+		return 2
+	}
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	format := fs.String("format", "yaml", "Output format (yaml/toml)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	out, err := printConfig(config, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	fmt.Print(out)
+	return 0
+}
+
+// printConfig renders the effective configuration with secrets redacted, in
+// a form that can be used as a config file.
+func printConfig(config *Config, format string) (string, error) {
+	safe := redactSecrets(reflect.ValueOf(*config)).Interface()
+	var buf bytes.Buffer
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(safe); err != nil {
+			return "", fmt.Errorf("marshal config: %w", err)
+		}
+	case "toml":
+		if err := toml.NewEncoder(&buf).Encode(safe); err != nil {
+			return "", fmt.Errorf("marshal config: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("invalid format %q, must be 'yaml' or 'toml'", format)
+	}
+	return buf.String(), nil
+}
+
+// redactSecrets returns a deep copy of v in which every non-empty string held
+// by a struct field tagged `secret:"true"` is replaced by redacted, however
+// deeply nested in structs, pointers, slices and maps.
+func redactSecrets(v reflect.Value) reflect.Value {
+	return redactValue(v, false)
+}
+
+func redactValue(v reflect.Value, secret bool) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		if secret && v.Len() > 0 {
+			out.SetString(redacted)
+		} else {
+			out.Set(v)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			out.Field(i).Set(redactValue(v.Field(i), secret || field.Tag.Get("secret") == "true"))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			elem := reflect.New(v.Type().Elem())
+			elem.Elem().Set(redactValue(v.Elem(), secret))
+			out.Set(elem)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			out.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				out.Index(i).Set(redactValue(v.Index(i), secret))
+			}
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			out.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			iter := v.MapRange()
+			for iter.Next() {
+				out.SetMapIndex(iter.Key(), redactValue(iter.Value(), secret))
+			}
+		}
+	default:
+		out.Set(v)
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ConnectionRegistry holds a WeaviateConnection per configured connection
+// name.
+type ConnectionRegistry struct {
+	mu      sync.RWMutex
+	conns   map[string]*WeaviateConnection
+	configs map[string]ConnectionConfig
+	primary string
+}
+
+// NewConnectionRegistry connects to every connection in config.
+func NewConnectionRegistry(config *Config, logger *Logger) (*ConnectionRegistry, error) {
+	registry := &ConnectionRegistry{}
+	if _, err := registry.Reload(config, logger); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// Reload brings the registry in line with config, reconnecting only the
+// connections whose settings changed, and returns their names. On error the
+// registry is left as it was.
+func (r *ConnectionRegistry) Reload(config *Config, logger *Logger) ([]string, error) {
+	configs := config.connectionConfigs()
+
+	r.mu.RLock()
+	conns := make(map[string]*WeaviateConnection, len(configs))
+	var changed []string
+	for _, name := range sortedKeys(configs) {
+		if conn, ok := r.conns[name]; ok && reflect.DeepEqual(r.configs[name], configs[name]) {
+			conns[name] = conn
+			continue
+		}
+		changed = append(changed, name)
+	}
+	r.mu.RUnlock()
+
+	for _, name := range changed {
+		conn, err := NewWeaviateConnection(name, configs[name], logger)
+		if err != nil {
+			return nil, err
+		}
+		conns[name] = conn
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns = conns
+	r.configs = configs
+	r.primary = config.primaryConnection()
+	return changed, nil
+}
+
+// Get returns the named connection, or the primary one when name is empty.
+func (r *ConnectionRegistry) Get(name string) (*WeaviateConnection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.primary
+	}
+	conn, ok := r.conns[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown connection '%s' (available: %s)", name, strings.Join(sortedKeys(r.conns), ", "))
+	}
+	return conn, nil
+}
+
+// Primary returns the connection used by calls that do not select one.
+func (r *ConnectionRegistry) Primary() *WeaviateConnection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.conns[r.primary]
+}
+
+// PrimaryName returns the name of the primary connection.
+func (r *ConnectionRegistry) PrimaryName() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.primary
+}
+
+// Names returns the sorted connection names.
+func (r *ConnectionRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return sortedKeys(r.conns)
+}
+
+// withConnectionOption adds the connection argument that every tool takes.
+func (s *MCPServer) withConnectionOption() mcp.ToolOption {
+	return mcp.WithString(
+		"connection",
+		mcp.Description(fmt.Sprintf("Named Weaviate connection to use (default: %s)", s.connections.PrimaryName())),
+		mcp.Enum(s.connections.Names()...),
+	)
+}
+
+// connection returns the connection selected by the call's connection
+// argument.
+func (s *MCPServer) connection(req mcp.CallToolRequest) (*WeaviateConnection, error) {
+	return s.connections.Get(req.GetString("connection", ""))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// httpEndpointPath is where the HTTP transport serves the MCP streamable
+// HTTP endpoint.
+const httpEndpointPath = "/mcp"
+
+const (
+	// corsAllowedHeaders are the request headers browsers may send to the
+	// MCP endpoint.
+	corsAllowedHeaders = "Authorization, Content-Type, Accept, Last-Event-ID, Mcp-Session-Id, Mcp-Protocol-Version, X-API-Key"
+	// corsExposedHeaders are the response headers browser clients need to
+	// read.
+	corsExposedHeaders = "Mcp-Session-Id, WWW-Authenticate"
+)
+
+// ServeHTTP serves the MCP streamable HTTP transport on host:port, over TLS
+// when a certificate is configured. Every request is authenticated first;
+// see authenticate.
+func (s *MCPServer) ServeHTTP(host string, port int) error {
+	config := s.currentConfig()
+	streamable := server.NewStreamableHTTPServer(s.server, server.WithEndpointPath(httpEndpointPath))
+
+	mux := http.NewServeMux()
+	mux.Handle(httpEndpointPath, s.authenticate(subscriptionHandler(streamable)))
+	mux.HandleFunc(resourceMetadataPath, s.handleResourceMetadata)
+	mux.HandleFunc(resourceMetadataPath+"/", s.handleResourceMetadata)
+
+	srv := &http.Server{
+		Addr:    net.JoinHostPort(host, strconv.Itoa(port)),
+		Handler: s.cors(mux),
+	}
+	if config.HTTPTLSCertFile == "" {
+		s.logger.Info("Serving MCP on http://%s%s", srv.Addr, httpEndpointPath)
+		return srv.ListenAndServe()
+	}
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return err
+	}
+	srv.TLSConfig = tlsConfig
+	s.logger.Info("Serving MCP on https://%s%s (client certificates required: %v)", srv.Addr, httpEndpointPath, config.HTTPTLSClientCAFile != "")
+	return srv.ListenAndServeTLS(config.HTTPTLSCertFile, config.HTTPTLSKeyFile)
+}
+
+// validateHTTPServer checks the TLS files and CORS origins of the HTTP
+// transport.
+func (c *Config) validateHTTPServer() error {
+	if (c.HTTPTLSCertFile == "") != (c.HTTPTLSKeyFile == "") {
+		return fmt.Errorf("http_tls_cert_file and http_tls_key_file must be set together")
+	}
+	if c.HTTPTLSClientCAFile != "" && c.HTTPTLSCertFile == "" {
+		return fmt.Errorf("http_tls_client_ca_file requires http_tls_cert_file and http_tls_key_file")
+	}
+	if c.HTTPTLSCertFile != "" {
+		if _, err := c.tlsConfig(); err != nil {
+			return err
+		}
+	}
+	for _, origin := range c.HTTPCORSAllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") || strings.TrimSuffix(u.Path, "/") != "" {
+			return fmt.Errorf("invalid CORS origin: %s, must be scheme://host[:port] or *", origin)
+		}
+	}
+	return nil
+}
+
+// tlsConfig checks that the certificate of the HTTP transport loads and,
+// with a client CA file, requires clients to present a certificate it
+// verifies.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if _, err := tls.LoadX509KeyPair(c.HTTPTLSCertFile, c.HTTPTLSKeyFile); err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.HTTPTLSClientCAFile != "" {
+		pem, err := os.ReadFile(c.HTTPTLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read TLS client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in TLS client CA file %s", c.HTTPTLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// isOriginAllowed reports whether browsers on origin may call the server.
+func (c *Config) isOriginAllowed(origin string) bool {
+	return slices.ContainsFunc(c.HTTPCORSAllowedOrigins, func(allowed string) bool {
+		return allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin)
+	})
+}
+
+// cors answers CORS preflight requests and adds the CORS headers for the
+// allowed origins. Requests from any other origin are rejected, which also
+// protects servers on loopback addresses from DNS rebinding; clients that
+// are not browsers send no Origin and are not affected. The origins are read
+// from the configuration in effect, so they can be changed with a reload.
+func (s *MCPServer) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !s.currentConfig().isOriginAllowed(origin) {
+			s.logger.Warn("Rejected HTTP request from %s: origin %s is not allowed", r.RemoteAddr, origin)
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		header := w.Header()
+		header.Set("Access-Control-Allow-Origin", origin)
+		header.Add("Vary", "Origin")
+		header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			header.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// subscriptionHandler applies rewriteSubscription to the JSON-RPC message in
+// the body of each POST request before passing it on to next.
+func subscriptionHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			body = rewriteSubscription(body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
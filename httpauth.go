@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// scopeNames maps the scopes of API keys to the tools they grant: each scope
+// includes the ones below it.
+var scopeNames = map[string]toolAccess{
+	"read":  toolRead,
+	"write": toolWrite,
+	"admin": toolAdmin,
+}
+
+func (access toolAccess) String() string {
+	for name, scope := range scopeNames {
+		if scope == access {
+			return name
+		}
+	}
+	return fmt.Sprintf("toolAccess(%d)", int(access))
+}
+
+// validateAPIKeys checks the HTTP API keys.
+func (c *Config) validateAPIKeys() error {
+	seen := make(map[string]bool)
+	for i, key := range c.HTTPAPIKeys {
+		if key.Name == "" {
+			return fmt.Errorf("http_api_keys: entry %d has no name", i+1)
+		}
+		if key.Key == "" {
+			return fmt.Errorf("http_api_keys %s: key is required", key.Name)
+		}
+		if _, ok := scopeNames[key.Scope]; !ok {
+			return fmt.Errorf("http_api_keys %s: invalid scope: %s, must be 'read', 'write' or 'admin'", key.Name, key.Scope)
+		}
+		if seen[key.Key] {
+			return fmt.Errorf("http_api_keys %s: key is used by another entry", key.Name)
+		}
+		seen[key.Key] = true
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether host only accepts connections from the
+// local machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// apiKey returns the configured API key matching token.
+func (c *Config) apiKey(token string) (APIKey, bool) {
+	var match APIKey
+	found := false
+	for _, key := range c.HTTPAPIKeys {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) == 1 {
+			match, found = key, true
+		}
+	}
+	return match, found
+}
+
+// caller is the authenticated client of an HTTP request. Collections, when
+// not nil, further limits the collections it may access to these patterns.
+type caller struct {
+	Name        string
+	Scope       toolAccess
+	Collections []string
+}
+
+type callerKey struct{}
+
+// callerFromContext returns the authenticated client of the request ctx
+// belongs to. There is none on stdio or when no API keys are configured,
+// and then every registered tool may be used.
+func callerFromContext(ctx context.Context) (caller, bool) {
+	c, ok := ctx.Value(callerKey{}).(caller)
+	return c, ok
+}
+
+// requestToken returns the API key sent with r as a bearer token or in the
+// X-API-Key header.
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// authenticate rejects requests without a valid API key or OAuth access
+// token and records the client in the context of the others. The settings
+// are read from the configuration in effect, so keys can be rotated with a
+// reload. Without API keys or OAuth every request is accepted.
+func (s *MCPServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config := s.currentConfig()
+		if len(config.HTTPAPIKeys) == 0 && !config.OAuth.enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token := requestToken(r)
+		if key, ok := config.apiKey(token); ok {
+			ctx := context.WithValue(r.Context(), callerKey{}, caller{Name: key.Name, Scope: scopeNames[key.Scope]})
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		if !config.OAuth.enabled() {
+			s.logger.Warn("Rejected unauthenticated HTTP request from %s", r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			http.Error(w, "missing or invalid API key", http.StatusUnauthorized)
+			return
+		}
+		challenge := fmt.Sprintf(`Bearer resource_metadata="%s"`, config.OAuth.resourceMetadataURL())
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, "missing access token", http.StatusUnauthorized)
+			return
+		}
+		c, err := s.verifyAccessToken(r.Context(), config.OAuth, token)
+		if errors.Is(err, errInsufficientScope) {
+			s.logger.Warn("Rejected HTTP request from %s by %s: %v", r.RemoteAddr, c.Name, err)
+			w.Header().Set("WWW-Authenticate", challenge+`, error="insufficient_scope"`)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			s.logger.Warn("Rejected HTTP request from %s: %v", r.RemoteAddr, err)
+			w.Header().Set("WWW-Authenticate", challenge+`, error="invalid_token"`)
+			http.Error(w, "invalid access token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, c)))
+	})
+}
+
+// toolScope returns the scope needed to see and call the named tool. Tools
+// not recorded by registerTool need the admin scope.
+func (s *MCPServer) toolScope(name string) toolAccess {
+	if access, ok := s.toolAccess.Load(name); ok {
+		return access.(toolAccess)
+	}
+	return toolAdmin
+}
+
+// filterToolsByScope leaves the tools the caller's scope does not grant out
+// of tools/list.
+func (s *MCPServer) filterToolsByScope(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	c, ok := callerFromContext(ctx)
+	if !ok {
+		return tools
+	}
+	visible := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if s.toolScope(tool.Name) <= c.Scope {
+			visible = append(visible, tool)
+		}
+	}
+	return visible
+}
+
+// filterResourcesByCaller leaves the resources of collections the caller's
+// token does not allow out of resources/list. Reading them is rejected by
+// the resource handlers.
+func (s *MCPServer) filterResourcesByCaller(ctx context.Context, id any, req *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+	if c, ok := callerFromContext(ctx); !ok || c.Collections == nil || result == nil {
+		return
+	}
+	visible := result.Resources[:0]
+	for _, resource := range result.Resources {
+		if collection, ok := resourceCollection(resource.URI); !ok || s.checkCollection(ctx, collection) == nil {
+			visible = append(visible, resource)
+		}
+	}
+	result.Resources = visible
+}
+
+// resourceCollection returns the collection of a per-collection resource URI
+// such as weaviate://schema/Article.
+func resourceCollection(uri string) (string, bool) {
+	rest, ok := strings.CutPrefix(uri, "weaviate://")
+	if !ok {
+		return "", false
+	}
+	_, collection, ok := strings.Cut(rest, "/")
+	collection, _, _ = strings.Cut(collection, "?")
+	return collection, ok && collection != ""
+}
+
+// scopeMiddleware rejects calls to tools the caller's scope does not grant.
+func (s *MCPServer) scopeMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		c, ok := callerFromContext(ctx)
+		if ok {
+			if needed := s.toolScope(req.Params.Name); needed > c.Scope {
+				s.logger.Warn("Denied %s call by %s: requires the %s scope", req.Params.Name, c.Name, needed)
+				return mcp.NewToolResultError(fmt.Sprintf("tool %s requires the %s scope, but %s has the %s scope", req.Params.Name, needed, c.Name, c.Scope)), nil
+			}
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolLimits caps the size of tool arguments. Zero means no limit.
+type ToolLimits struct {
+	MaxLimit            int `yaml:"max_limit" toml:"max_limit"`                         // limit, i.e. the page size of a search
+	MaxTargetProperties int `yaml:"max_target_properties" toml:"max_target_properties"` // entries in targetProperties
+	MaxQueryLength      int `yaml:"max_query_length" toml:"max_query_length"`           // runes in query
+	MaxFilterNodes      int `yaml:"max_filter_nodes" toml:"max_filter_nodes"`           // operators in a where/filters argument
+	MaxBatchSize        int `yaml:"max_batch_size" toml:"max_batch_size"`               // entries in objects
+}
+
+// filterArguments are the tool arguments holding a Weaviate where filter.
+var filterArguments = []string{"where", "filters"}
+
+// toolLimits returns the limits of tool: the global limits, overridden by
+// the non-zero settings in PerToolLimits for that tool.
+func (c *Config) toolLimits(tool string) ToolLimits {
+	limits := c.Limits
+	override := c.PerToolLimits[tool]
+	if override.MaxLimit != 0 {
+		limits.MaxLimit = override.MaxLimit
+	}
+	if override.MaxTargetProperties != 0 {
+		limits.MaxTargetProperties = override.MaxTargetProperties
+	}
+	if override.MaxQueryLength != 0 {
+		limits.MaxQueryLength = override.MaxQueryLength
+	}
+	if override.MaxFilterNodes != 0 {
+		limits.MaxFilterNodes = override.MaxFilterNodes
+	}
+	if override.MaxBatchSize != 0 {
+		limits.MaxBatchSize = override.MaxBatchSize
+	}
+	return limits
+}
+
+// validate rejects negative limits.
+func (l ToolLimits) validate() error {
+	settings := []struct {
+		name  string
+		value int
+	}{
+		{"max_limit", l.MaxLimit},
+		{"max_target_properties", l.MaxTargetProperties},
+		{"max_query_length", l.MaxQueryLength},
+		{"max_filter_nodes", l.MaxFilterNodes},
+		{"max_batch_size", l.MaxBatchSize},
+	}
+	for _, setting := range settings {
+		if setting.value < 0 {
+			return fmt.Errorf("invalid %s: %d", setting.name, setting.value)
+		}
+	}
+	return nil
+}
+
+// check returns an error for the first argument of a call to tool that
+// exceeds the limits.
+func (l ToolLimits) check(tool string, args map[string]interface{}) error {
+	if raw, ok := args["limit"]; ok {
+		limit, ok := raw.(float64)
+		if !ok || limit != math.Trunc(limit) || limit < 1 {
+			return fmt.Errorf("'limit' must be a positive whole number, got %v", raw)
+		}
+		if l.MaxLimit > 0 && limit > float64(l.MaxLimit) {
+			return fmt.Errorf("'limit' is %v but %s returns at most %d results per call; lower it", limit, tool, l.MaxLimit)
+		}
+	}
+	if props, ok := args["targetProperties"].([]interface{}); ok && l.MaxTargetProperties > 0 && len(props) > l.MaxTargetProperties {
+		return fmt.Errorf("'targetProperties' has %d entries but %s accepts at most %d; select fewer properties", len(props), tool, l.MaxTargetProperties)
+	}
+	if query, ok := args["query"].(string); ok && l.MaxQueryLength > 0 {
+		if n := utf8.RuneCountInString(query); n > l.MaxQueryLength {
+			return fmt.Errorf("'query' is %d characters long but %s accepts at most %d; shorten it", n, tool, l.MaxQueryLength)
+		}
+	}
+	for _, arg := range filterArguments {
+		if filter, ok := args[arg]; ok && l.MaxFilterNodes > 0 {
+			if n := countFilterNodes(filter); n > l.MaxFilterNodes {
+				return fmt.Errorf("'%s' has %d operators but %s accepts at most %d; simplify the filter", arg, n, tool, l.MaxFilterNodes)
+			}
+		}
+	}
+	if objs, ok := args["objects"].([]interface{}); ok && l.MaxBatchSize > 0 && len(objs) > l.MaxBatchSize {
+		return fmt.Errorf("'objects' has %d entries but %s inserts at most %d per call; split the batch", len(objs), tool, l.MaxBatchSize)
+	}
+	return nil
+}
+
+// countFilterNodes counts the operators in a where filter, nested through
+// its operands.
+func countFilterNodes(filter interface{}) int {
+	node, ok := filter.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	count := 1
+	operands, _ := node["operands"].([]interface{})
+	for _, operand := range operands {
+		count += countFilterNodes(operand)
+	}
+	return count
+}
+
+// withLimits wraps handler so that calls exceeding the limits of the tool
+// fail before reaching Weaviate.
+func (s *MCPServer) withLimits(tool string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := s.currentConfig().toolLimits(tool).check(tool, req.GetArguments()); err != nil {
+			s.logger.Warn("Rejected %s call: %v", tool, err)
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return handler(ctx, req)
+	}
+}
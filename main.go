@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -17,9 +18,22 @@ func main() {
 
 	// Initialize logger
 	logger := NewLogger(config)
+
+	// Run a CLI subcommand instead of the server, e.g. "schema apply -f schema.yaml"
+	if args := flag.Args(); len(args) > 0 {
+		switch args[0] {
+		case "schema":
+			os.Exit(runSchemaCommand(args[1:], config, logger))
+		case "config":
+			os.Exit(runConfigCommand(args[1:], config))
+		default:
+			log.Fatalf("Unknown command: %s", args[0])
+		}
+	}
+
 	logger.Info("Starting Weaviate MCP Server v0.1.0")
-	logger.Info("Configuration: host=%s, scheme=%s, transport=%s, read-only=%v",
-		config.WeaviateHost, config.WeaviateScheme, config.Transport, config.ReadOnly)
+	logger.Info("Configuration: connections=%v, primary=%s, transport=%s, read-only=%v",
+		sortedKeys(config.connectionConfigs()), config.primaryConnection(), config.Transport, config.ReadOnly)
 
 	// Create MCP server
 	server, err := NewMCPServer(config, logger)
@@ -32,6 +46,18 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// Report problems with Weaviate up front rather than on the first tool call
+	server.SelfCheck(ctx)
+
+	// Keep schema resources in sync with collections created or dropped later
+	go server.WatchSchema(ctx, config.SchemaRefreshInterval)
+
+	// Notify subscribed clients when the resources they watch change
+	go server.WatchSubscriptions(ctx, config.SubscriptionPollInterval)
+
+	// Apply config changes on SIGHUP or when the config file is edited
+	go server.WatchConfig(ctx, config.ConfigWatchInterval)
+
 	// Start server based on transport
 	switch config.Transport {
 	case "stdio":
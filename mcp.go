@@ -3,43 +3,81 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/weaviate/weaviate/entities/models"
 )
 
 type MCPServer struct {
-	server            *server.MCPServer
-	weaviateConn      *WeaviateConnection
-	defaultCollection string
-	config            *Config
-	logger            *Logger
+	server      *server.MCPServer
+	connections *ConnectionRegistry
+	logger      *Logger
+
+	// config is replaced as a whole by Reload; read it with currentConfig.
+	configMu sync.RWMutex
+	config   *Config
+
+	// collections is the sorted list of collections registered as resources
+	// by the last RefreshResources, nil before the first successful refresh.
+	resourcesMu sync.Mutex
+	collections []string
+
+	// subscribable maps the URI prefix of each resource that supports
+	// resources/subscribe to the fingerprint used to detect its changes.
+	subscriptions *subscriptions
+	subscribable  map[string]resourceFingerprint
+
+	// toolAccess records the toolAccess of each registered tool by name,
+	// which is the scope HTTP clients need to see and call it.
+	toolAccess sync.Map
+
+	// jwks caches the signing keys of the OAuth issuer.
+	jwks keySet
 }
 
 func NewMCPServer(config *Config, logger *Logger) (*MCPServer, error) {
-	logger.Info("Initializing Weaviate connection...")
-	conn, err := NewWeaviateConnection(config, logger)
+	logger.Info("Initializing Weaviate connections...")
+	connections, err := NewConnectionRegistry(config, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Weaviate connection: %w", err)
 	}
 
 	s := &MCPServer{
-		server: server.NewMCPServer(
-			"Weaviate MCP Server",
-			"0.1.0",
-			server.WithToolCapabilities(true),
-			server.WithPromptCapabilities(false),
-			server.WithResourceCapabilities(true, true),
-			server.WithRecovery(),
-		),
-		weaviateConn:      conn,
-		defaultCollection: config.DefaultCollection,
-		config:            config,
-		logger:            logger,
+		connections:   connections,
+		config:        config,
+		logger:        logger,
+		subscriptions: newSubscriptions(),
+	}
+	s.subscribable = map[string]resourceFingerprint{
+		"weaviate://schema/": s.schemaFingerprint,
+		"weaviate://stats/":  s.statsFingerprint,
 	}
 
+	hooks := &server.Hooks{}
+	s.subscriptionHooks(hooks)
+	hooks.AddAfterListResources(s.filterResourcesByCaller)
+
+	s.server = server.NewMCPServer(
+		"Weaviate MCP Server",
+		"0.1.0",
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(false),
+		server.WithResourceCapabilities(true, true),
+		server.WithHooks(hooks),
+		server.WithToolFilter(s.filterToolsByScope),
+		server.WithToolHandlerMiddleware(s.scopeMiddleware),
+		server.WithRecovery(),
+	)
+
 	logger.Info("Registering tools...")
 	s.registerTools()
 
@@ -60,12 +98,8 @@ func (s *MCPServer) Serve() {
 
 func (s *MCPServer) ServeStdio() error {
 	s.logger.Info("Starting stdio server...")
-	return server.ServeStdio(s.server)
-}
-
-func (s *MCPServer) ServeHTTP(host string, port int) error {
-	s.logger.Info("HTTP transport not yet implemented, falling back to stdio")
-	return server.ServeStdio(s.server)
+	stdio := server.NewStdioServer(s.server)
+	return stdio.Listen(context.Background(), newSubscriptionReader(os.Stdin), os.Stdout)
 }
 
 func (s *MCPServer) registerTools() {
@@ -74,6 +108,8 @@ func (s *MCPServer) registerTools() {
 	// For now let's just implement the weaviate-query tool
 	// and leave weaviate-insert-one commented out
 	// until we finalize the design for inserts.
+	// It already takes tenant and consistencyLevel; until it is registered,
+	// only the search and reference tools do.
 	// if !s.config.IsToolDisabled("weaviate-insert-one") && !s.config.ReadOnly {
 	// 	insertOne := mcp.NewTool(
 	// 		"weaviate-insert-one",
@@ -86,6 +122,8 @@ func (s *MCPServer) registerTools() {
 	// 			mcp.Description("Object properties to insert"),
 	// 			mcp.Required(),
 	// 		),
+	// 		withTenantOption(),
+	// 		withConsistencyLevelOption(),
 	// 	)
 	// 	tools = append(tools, server.ServerTool{Tool: insertOne, Handler: s.weaviateInsertOne})
 	// 	s.logger.Info("Registered tool: weaviate-insert-one")
@@ -96,57 +134,196 @@ func (s *MCPServer) registerTools() {
 	// }
 
 	// weaviate-query tool
-	if !s.config.IsToolDisabled("weaviate-query") {
-		query := mcp.NewTool(
-			"weaviate-query",
-			mcp.WithDescription("Query objects from a Weaviate collection using hybrid search"),
-			mcp.WithString(
-				"query",
-				mcp.Description("Query data within Weaviate"),
-				mcp.Required(),
-			),
-			mcp.WithString(
-				"collection",
-				mcp.Description("Name of the target collection"),
-				mcp.Required(),
-			),
-			mcp.WithArray(
-				"targetProperties",
-				mcp.Description("Properties to return with the query. Check available properties via weaviate://schema/{collection} resources"),
-				mcp.Required(),
-			),
-			mcp.WithNumber(
-				"limit",
-				mcp.DefaultNumber(3),
-				mcp.Description("Maximum number of results to return (default: 3)"),
-			),
-		)
+	query := mcp.NewTool(
+		"weaviate-query",
+		mcp.WithDescription("Query objects from a Weaviate collection using hybrid search"),
+		mcp.WithString(
+			"query",
+			mcp.Description("Query data within Weaviate"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"targetProperties",
+			mcp.Description("Properties to return with the query. Check available properties via weaviate://schema/{collection} resources. Cross-reference properties are traversed with dotted paths of the form property.Collection.property, e.g. hasAuthor.Author.name"),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.Required(),
+		),
+		s.withLimitOption("weaviate-query"),
+		withTargetVectorOptions(),
+		withTenantOption(),
+		withConsistencyLevelOption(),
+	)
+	tools = append(tools, s.registerTool(query, s.weaviateQuery, toolRead)...)
+
+	tools = append(tools, s.collectionTools()...)
+
+	// weaviate-near-text tool
+	nearText := mcp.NewTool(
+		"weaviate-near-text",
+		mcp.WithDescription("Query objects from a Weaviate collection using pure vector (nearText) search"),
+		mcp.WithString(
+			"query",
+			mcp.Description("Concept to search for"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"targetProperties",
+			mcp.Description("Properties to return with the query. Check available properties via weaviate://schema/{collection} resources. Cross-reference properties are traversed with dotted paths of the form property.Collection.property, e.g. hasAuthor.Author.name"),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.Required(),
+		),
+		s.withLimitOption("weaviate-near-text"),
+		withTargetVectorOptions(),
+		withTenantOption(),
+		withConsistencyLevelOption(),
+	)
+	tools = append(tools, s.registerTool(nearText, s.weaviateNearText, toolRead)...)
+
+	// weaviate-list-named-vectors tool
+	listVectors := mcp.NewTool(
+		"weaviate-list-named-vectors",
+		mcp.WithDescription("List the named vectors of a Weaviate collection, for use as targetVectors in searches"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+	)
+	tools = append(tools, s.registerTool(listVectors, s.weaviateListNamedVectors, toolRead)...)
 
-		// Optional: log the schema to catch issues early
-		if b, err := json.MarshalIndent(query.InputSchema, "", "  "); err == nil {
-			s.logger.Debug("weaviate-query schema before modification:\n" + string(b))
-		}
+	// weaviate-cluster-status tool
+	clusterStatus := mcp.NewTool(
+		"weaviate-cluster-status",
+		mcp.WithDescription("Report Weaviate health: liveness, readiness, version, enabled modules, node status, and shard status and object counts per collection. Use it to tell whether a failed call was caused by an unhealthy cluster"),
+	)
+	tools = append(tools, s.registerTool(clusterStatus, s.weaviateClusterStatus, toolRead)...)
 
-		// Fix the array schema by adding missing "items" property
-		if query.InputSchema.Properties != nil {
-			if targetProps, ok := query.InputSchema.Properties["targetProperties"].(map[string]interface{}); ok {
-				targetProps["items"] = map[string]interface{}{"type": "string"}
-				targetProps["minItems"] = 1
-			}
-		}
+	tools = append(tools, s.referenceTools()...)
+	tools = append(tools, s.tenantTools()...)
+	tools = append(tools, s.backupTools()...)
 
-		// Log schema after modification
-		if b, err := json.MarshalIndent(query.InputSchema, "", "  "); err == nil {
-			s.logger.Debug("weaviate-query schema after modification:\n" + string(b))
+	// Forget the access levels of tools a reload no longer registers
+	registered := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		registered[tool.Tool.Name] = true
+	}
+	s.toolAccess.Range(func(name, _ any) bool {
+		if !registered[name.(string)] {
+			s.toolAccess.Delete(name)
 		}
+		return true
+	})
+
+	// SetTools replaces the tools of a previous registration and emits
+	// notifications/tools/list_changed
+	s.server.SetTools(tools...)
+}
+
+// withLimitOption adds the limit argument of the search tools, bounded by the
+// tool's MaxLimit.
+func (s *MCPServer) withLimitOption(tool string) mcp.ToolOption {
+	opts := []mcp.PropertyOption{
+		mcp.DefaultNumber(3),
+		mcp.Min(1),
+		mcp.Description("Maximum number of results to return (default: 3)"),
+	}
+	if max := s.currentConfig().toolLimits(tool).MaxLimit; max > 0 {
+		opts = append(opts,
+			mcp.Max(float64(max)),
+			mcp.Description(fmt.Sprintf("Maximum number of results to return (default: 3, at most %d)", max)),
+		)
+	}
+	return mcp.WithNumber("limit", opts...)
+}
 
-		tools = append(tools, server.ServerTool{Tool: query, Handler: s.weaviateQuery})
-		s.logger.Info("Registered tool: weaviate-query")
-	} else {
-		s.logger.Info("Skipped tool weaviate-query: disabled")
+// withTargetVectorOptions adds the named vector arguments shared by the
+// search tools.
+func withTargetVectorOptions() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithArray(
+			"targetVectors",
+			mcp.Description("Named vectors to search. List them with weaviate-list-named-vectors. Required for collections with more than one named vector"),
+			mcp.WithStringItems(),
+		)(t)
+		mcp.WithString(
+			"combination",
+			mcp.Description("How scores from several targetVectors are combined"),
+			mcp.Enum("sum", "average", "minimum", "manualWeights", "relativeScore"),
+		)(t)
+		mcp.WithObject(
+			"targetVectorWeights",
+			mcp.Description("Weight per named vector, required for the manualWeights and relativeScore combinations, e.g. {\"title\": 0.7, \"body\": 0.3}"),
+			mcp.AdditionalProperties(map[string]any{"type": "number"}),
+		)(t)
 	}
+}
+
+// withConsistencyLevelOption adds the consistencyLevel argument shared by the
+// tools that read or write objects.
+func withConsistencyLevelOption() mcp.ToolOption {
+	return mcp.WithString(
+		"consistencyLevel",
+		mcp.Description("Replication consistency level for this call, overriding the server default. Use QUORUM or ALL to read your own writes"),
+		mcp.Enum(consistencyLevels...),
+	)
+}
 
-	s.server.AddTools(tools...)
+// parseConsistencyLevel reads the optional consistencyLevel argument.
+func parseConsistencyLevel(req mcp.CallToolRequest) (string, error) {
+	level := req.GetString("consistencyLevel", "")
+	if level != "" && !slices.Contains(consistencyLevels, level) {
+		return "", fmt.Errorf("invalid consistencyLevel '%s', must be one of %s", level, strings.Join(consistencyLevels, ", "))
+	}
+	return level, nil
+}
+
+// toolAccess classifies tools by what they can do to Weaviate, which decides
+// the settings under which they are registered and the scope HTTP clients
+// need to see and call them.
+type toolAccess int
+
+const (
+	// toolRead tools only read data and are always registered.
+	toolRead toolAccess = iota
+	// toolWrite tools write objects and are skipped in read-only mode.
+	toolWrite
+	// toolAdmin tools change collections or cluster state. They are skipped
+	// in read-only mode and additionally require EnableAdminTools.
+	toolAdmin
+)
+
+// registerTool returns tool as a single-entry slice ready to be appended to
+// the registered tools, or nil when the tool is disabled or not allowed by
+// the read-only and admin settings.
+func (s *MCPServer) registerTool(tool mcp.Tool, handler server.ToolHandlerFunc, access toolAccess) []server.ServerTool {
+	if s.currentConfig().IsToolDisabled(tool.Name) {
+		s.logger.Info("Skipped tool %s: disabled", tool.Name)
+		return nil
+	}
+	if access >= toolWrite && s.currentConfig().ReadOnly {
+		s.logger.Info("Skipped tool %s: read-only mode enabled", tool.Name)
+		return nil
+	}
+	if access == toolAdmin && !s.currentConfig().EnableAdminTools {
+		s.logger.Info("Skipped tool %s: admin tools not enabled", tool.Name)
+		return nil
+	}
+	s.withConnectionOption()(&tool)
+	s.toolAccess.Store(tool.Name, access)
+	s.logger.Info("Registered tool: %s", tool.Name)
+	return []server.ServerTool{{Tool: tool, Handler: s.withLimits(tool.Name, s.withCollectionCheck(handler))}}
 }
 
 func (s *MCPServer) registerPrompts() {
@@ -250,28 +427,129 @@ func (s *MCPServer) registerPrompts() {
 }
 
 func (s *MCPServer) registerResources() {
-	var resources []server.ServerResource
+	// The templates serve any collection, including ones created after
+	// startup; the concrete resources below only make collections show up
+	// in resources/list and are kept in sync by RefreshResources.
+	template := mcp.NewResourceTemplate(
+		"weaviate://schema/{collection}",
+		"Collection schema",
+		mcp.WithTemplateDescription("Typed schema of a Weaviate collection"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(template, s.handleSchemaResource)
 
-	// Get the full schema to list all collections
-	schema, err := s.weaviateConn.client.Schema().Getter().Do(context.Background())
-	if err != nil {
+	statsTemplate := mcp.NewResourceTemplate(
+		"weaviate://stats/{collection}",
+		"Collection statistics",
+		mcp.WithTemplateDescription("Object count, per-property null counts and top values of a Weaviate collection"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(statsTemplate, s.handleStatsResource)
+
+	sampleTemplate := mcp.NewResourceTemplate(
+		"weaviate://sample/{collection}{?consistencyLevel}",
+		"Collection sample objects",
+		mcp.WithTemplateDescription("A handful of example objects from a Weaviate collection, with long strings truncated. consistencyLevel (ONE, QUORUM or ALL) overrides the connection's default"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(sampleTemplate, s.handleSampleResource)
+
+	if err := s.RefreshResources(context.Background()); err != nil {
 		s.logger.Error("Failed to get schema for resources: %v", err)
-		return
 	}
+}
 
+// RefreshResources registers a resource per collection in the live schema of
+// the primary connection, replacing the previous set. Clients are sent
+// notifications/resources/list_changed only when collections appeared or
+// disappeared since the last refresh.
+func (s *MCPServer) RefreshResources(ctx context.Context) error {
+	schema, err := s.connections.Primary().GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	// The resource list is shared by all clients, so it is only filtered by
+	// the configuration; filterResourcesByCaller narrows it per request
+	config := s.currentConfig()
+	collections := make([]string, 0, len(schema.Classes))
 	for _, class := range schema.Classes {
-		resource := mcp.NewResource(
-			fmt.Sprintf("weaviate://schema/%s", class.Class),
-			fmt.Sprintf("Schema for collection %s", class.Class),
+		if config.CheckCollection(class.Class) == nil {
+			collections = append(collections, class.Class)
+		}
+	}
+	sort.Strings(collections)
+
+	s.resourcesMu.Lock()
+	defer s.resourcesMu.Unlock()
+	if s.collections != nil && slices.Equal(s.collections, collections) {
+		return nil
+	}
+	s.collections = collections
+
+	resources := make([]server.ServerResource, 0, 3*len(collections))
+	for _, collection := range collections {
+		resources = append(resources,
+			server.ServerResource{
+				Resource: mcp.NewResource(
+					fmt.Sprintf("weaviate://schema/%s", collection),
+					fmt.Sprintf("Schema for collection %s", collection),
+					mcp.WithMIMEType("application/json"),
+				),
+				Handler: s.handleSchemaResource,
+			},
+			server.ServerResource{
+				Resource: mcp.NewResource(
+					fmt.Sprintf("weaviate://stats/%s", collection),
+					fmt.Sprintf("Statistics for collection %s", collection),
+					mcp.WithMIMEType("application/json"),
+				),
+				Handler: s.handleStatsResource,
+			},
+			server.ServerResource{
+				Resource: mcp.NewResource(
+					fmt.Sprintf("weaviate://sample/%s", collection),
+					fmt.Sprintf("Sample objects for collection %s", collection),
+					mcp.WithMIMEType("application/json"),
+				),
+				Handler: s.handleSampleResource,
+			},
 		)
-		resources = append(resources, server.ServerResource{
-			Resource: resource,
-			Handler:  s.handleSchemaResource,
-		})
 	}
 
-	s.server.AddResources(resources...)
+	// SetResources emits notifications/resources/list_changed
+	s.server.SetResources(resources...)
 	s.logger.Info("Registered %d resources", len(resources))
+	return nil
+}
+
+// WatchSchema refreshes the per-collection resources every interval until ctx
+// is done. An interval of zero disables polling; resources are then only
+// refreshed on demand.
+func (s *MCPServer) WatchSchema(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RefreshResources(ctx); err != nil {
+				s.logger.Warn("Failed to refresh schema resources: %v", err)
+			}
+		}
+	}
+}
+
+// knownCollection reports whether collection was in the schema at the last
+// refresh.
+func (s *MCPServer) knownCollection(collection string) bool {
+	s.resourcesMu.Lock()
+	defer s.resourcesMu.Unlock()
+	_, found := slices.BinarySearch(s.collections, collection)
+	return found
 }
 
 func (s *MCPServer) handleSchemaResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
@@ -281,23 +559,38 @@ func (s *MCPServer) handleSchemaResource(ctx context.Context, req mcp.ReadResour
 		return nil, fmt.Errorf("invalid resource URI: %s", uri)
 	}
 	collection := strings.TrimPrefix(uri, "weaviate://schema/")
+	if err := s.checkCollection(ctx, collection); err != nil {
+		return nil, err
+	}
 
-	classSchema, err := s.weaviateConn.GetClassSchema(ctx, collection)
+	classSchema, err := s.connections.Primary().GetClassSchema(ctx, collection)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schema for collection %s: %w", collection, err)
 	}
-
-	var properties []string
-	for _, prop := range classSchema.Properties {
-		properties = append(properties, prop.Name)
+	// A collection read through the template but missing from resources/list
+	// was created since the last refresh, so refresh now rather than waiting
+	// for the next poll.
+	if !s.knownCollection(collection) {
+		if err := s.RefreshResources(ctx); err != nil {
+			s.logger.Warn("Failed to refresh schema resources: %v", err)
+		}
 	}
 
-	content := fmt.Sprintf("Properties for collection '%s': %s", collection, strings.Join(properties, ", "))
+	schema := describeCollection(s.currentConfig().redactClass(classSchema))
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema for collection %s: %w", collection, err)
+	}
 	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(b),
+		},
 		mcp.TextResourceContents{
 			URI:      uri,
 			MIMEType: "text/plain",
-			Text:     content,
+			Text:     schema.Render(),
 		},
 	}, nil
 }
@@ -305,7 +598,11 @@ func (s *MCPServer) handleSchemaResource(ctx context.Context, req mcp.ReadResour
 func (s *MCPServer) weaviateInsertOne(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
 	s.logger.Debug("InsertOne called: collection=%v, args=%v", args["collection"], args)
-	targetCol := s.parseTargetCollection(req)
+	targetCol, err := s.parseTargetCollection(ctx, req)
+	if err != nil {
+		s.logger.Error("Invalid collection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	propsRaw, ok := args["properties"]
 	if !ok {
 		s.logger.Error("Missing 'properties' argument")
@@ -316,7 +613,22 @@ func (s *MCPServer) weaviateInsertOne(ctx context.Context, req mcp.CallToolReque
 		s.logger.Error("'properties' argument is not a map: %T", propsRaw)
 		return mcp.NewToolResultError("'properties' argument must be an object"), nil
 	}
-	res, err := s.weaviateConn.InsertOne(context.Background(), targetCol, props)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tenant, err := s.resolveTenant(ctx, conn, targetCol, req.GetString("tenant", ""))
+	if err != nil {
+		s.logger.Error("Invalid tenant: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	level, err := parseConsistencyLevel(req)
+	if err != nil {
+		s.logger.Error("Invalid consistency level: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	res, err := conn.InsertOne(ctx, targetCol, props, tenant, level)
 	if err != nil {
 		s.logger.Error("InsertOne error: %v", err)
 		return mcp.NewToolResultErrorFromErr("failed to insert object", err), nil
@@ -326,9 +638,30 @@ func (s *MCPServer) weaviateInsertOne(ctx context.Context, req mcp.CallToolReque
 }
 
 func (s *MCPServer) weaviateQuery(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.search(ctx, req, "Query", (*WeaviateConnection).Query)
+}
+
+func (s *MCPServer) weaviateNearText(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.search(ctx, req, "NearText", (*WeaviateConnection).NearText)
+}
+
+// search parses and validates the arguments shared by the search tools and
+// runs the search with fn. name is only used for logging.
+func (s *MCPServer) search(ctx context.Context, req mcp.CallToolRequest, name string,
+	fn func(conn *WeaviateConnection, ctx context.Context, collection, query string, targetProps []string, opts SearchOptions) (*models.GraphQLResponse, error),
+) (*mcp.CallToolResult, error) {
 	args := req.GetArguments()
-	s.logger.Debug("Query called: collection=%v, args=%v", args["collection"], args)
-	targetCol := s.parseTargetCollection(req)
+	s.logger.Debug("%s called: collection=%v, args=%v", name, args["collection"], args)
+	targetCol, err := s.parseTargetCollection(ctx, req)
+	if err != nil {
+		s.logger.Error("Invalid collection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 	queryRaw, ok := args["query"]
 	if !ok {
 		s.logger.Error("Missing 'query' argument")
@@ -363,50 +696,249 @@ func (s *MCPServer) weaviateQuery(ctx context.Context, req mcp.CallToolRequest)
 		return mcp.NewToolResultError("targetProperties must contain at least one property name"), nil
 	}
 	// Handle limit parameter (default to 3)
-	limit := 3
+	opts := SearchOptions{Limit: 3}
 	if limitRaw, ok := args["limit"]; ok {
 		if limitFloat, ok := limitRaw.(float64); ok {
-			limit = int(limitFloat)
+			opts.Limit = int(limitFloat)
 		} else {
 			s.logger.Error("'limit' argument is not a number: %T", limitRaw)
 			return mcp.NewToolResultError("'limit' argument must be a number"), nil
 		}
 	}
-	// Validate targetProps against schema
-	classSchema, err := s.weaviateConn.GetClassSchema(context.Background(), targetCol)
-	if err != nil {
-		s.logger.Error("Failed to get schema for collection %s: %v", targetCol, err)
-		return mcp.NewToolResultErrorFromErr("failed to get collection schema", err), nil
+	if opts.ConsistencyLevel, err = parseConsistencyLevel(req); err != nil {
+		s.logger.Error("Invalid consistency level: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
 	}
-	propertyMap := make(map[string]bool)
-	for _, prop := range classSchema.Properties {
-		propertyMap[prop.Name] = true
+	if err := parseTargetVectors(req, &opts); err != nil {
+		s.logger.Error("Invalid target vector arguments: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
 	}
+	// Validate targetProps against schema, following reference paths
+	schemas := make(map[string]*models.Class)
 	for _, prop := range targetProps {
-		if !propertyMap[prop] {
-			s.logger.Error("Invalid property '%s' for collection '%s'", prop, targetCol)
-			return mcp.NewToolResultError(fmt.Sprintf("property '%s' does not exist in collection '%s'", prop, targetCol)), nil
+		if err := s.validatePropertyPath(ctx, conn, targetCol, prop, schemas); err != nil {
+			var pathErr *propertyPathError
+			if errors.As(err, &pathErr) {
+				s.logger.Error("Invalid property '%s' for collection '%s': %v", prop, targetCol, err)
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			s.logger.Error("Failed to get schema while validating '%s': %v", prop, err)
+			return mcp.NewToolResultErrorFromErr("failed to get collection schema", err), nil
+		}
+	}
+	if opts.Tenant, err = s.resolveTenant(ctx, conn, targetCol, req.GetString("tenant", "")); err != nil {
+		s.logger.Error("Invalid tenant: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if names := opts.targetVectorNames(); len(names) > 0 {
+		valid := namedVectors(schemas[targetCol])
+		for _, name := range names {
+			if _, ok := valid[name]; !ok {
+				s.logger.Error("Invalid target vector '%s' for collection '%s'", name, targetCol)
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"named vector '%s' does not exist in collection '%s' (available: %s)",
+					name, targetCol, strings.Join(sortedKeys(valid), ", "))), nil
+			}
 		}
 	}
-	res, err := s.weaviateConn.Query(context.Background(), targetCol, query, targetProps, limit)
+	res, err := fn(conn, ctx, targetCol, query, targetProps, opts)
 	if err != nil {
-		s.logger.Error("Query error: %v", err)
+		s.logger.Error("%s error: %v", name, err)
 		return mcp.NewToolResultErrorFromErr("failed to process query", err), nil
 	}
-	s.logger.Info("Query success: result length=%d", len(res))
-	return mcp.NewToolResultText(res), nil
+	s.currentConfig().redactSearchResult(targetCol, targetProps, res)
+	b, err := json.Marshal(res)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal query response", err), nil
+	}
+	s.logger.Info("%s success: result length=%d", name, len(b))
+	return mcp.NewToolResultText(string(b)), nil
+}
+
+// parseTargetVectors reads the optional targetVectors, combination and
+// targetVectorWeights arguments into opts.
+func parseTargetVectors(req mcp.CallToolRequest, opts *SearchOptions) error {
+	args := req.GetArguments()
+	if raw, ok := args["targetVectors"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("'targetVectors' argument must be an array")
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("targetVectors must contain only strings")
+			}
+			opts.TargetVectors = append(opts.TargetVectors, name)
+		}
+	}
+	if raw, ok := args["targetVectorWeights"]; ok {
+		weights, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("'targetVectorWeights' argument must be an object")
+		}
+		opts.Weights = make(map[string]float32, len(weights))
+		for name, w := range weights {
+			f, ok := w.(float64)
+			if !ok {
+				return fmt.Errorf("weight for target vector '%s' must be a number", name)
+			}
+			opts.Weights[name] = float32(f)
+		}
+	}
+	opts.Combination = req.GetString("combination", "")
+
+	switch opts.Combination {
+	case "":
+		if len(opts.Weights) > 0 {
+			return fmt.Errorf("'targetVectorWeights' requires combination 'manualWeights' or 'relativeScore'")
+		}
+	case "sum", "average", "minimum":
+		if len(opts.TargetVectors) < 2 {
+			return fmt.Errorf("combination '%s' requires at least two targetVectors", opts.Combination)
+		}
+		if len(opts.Weights) > 0 {
+			return fmt.Errorf("'targetVectorWeights' is only used with combination 'manualWeights' or 'relativeScore'")
+		}
+	case "manualWeights", "relativeScore":
+		if len(opts.Weights) == 0 {
+			return fmt.Errorf("combination '%s' requires 'targetVectorWeights'", opts.Combination)
+		}
+		for _, name := range opts.TargetVectors {
+			if _, ok := opts.Weights[name]; !ok {
+				return fmt.Errorf("target vector '%s' has no weight in 'targetVectorWeights'", name)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid combination '%s', must be one of sum, average, minimum, manualWeights, relativeScore", opts.Combination)
+	}
+	return nil
+}
+
+func (s *MCPServer) weaviateListNamedVectors(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	targetCol, err := s.parseTargetCollection(ctx, req)
+	if err != nil {
+		s.logger.Error("Invalid collection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	s.logger.Debug("ListNamedVectors called: collection=%s", targetCol)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	classSchema, err := conn.GetClassSchema(ctx, targetCol)
+	if err != nil {
+		s.logger.Error("Failed to get schema for collection %s: %v", targetCol, err)
+		return mcp.NewToolResultErrorFromErr("failed to get collection schema", err), nil
+	}
+	vectors := namedVectors(classSchema)
+	list := make([]NamedVector, 0, len(vectors))
+	for _, name := range sortedKeys(vectors) {
+		list = append(list, vectors[name])
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal named vectors", err), nil
+	}
+	s.logger.Info("ListNamedVectors success: collection=%s, vectors=%d", targetCol, len(list))
+	return mcp.NewToolResultText(string(b)), nil
+}
+
+// propertyPathError reports a targetProperties entry that does not match the
+// schema, as opposed to a failure to fetch the schema itself.
+type propertyPathError struct {
+	msg string
+}
+
+func (e *propertyPathError) Error() string {
+	return e.msg
+}
+
+// validatePropertyPath checks a targetProperties entry against the schema. A
+// plain entry must name a non-reference property of collection. A dotted entry
+// such as "hasAuthor.Author.name" is read as property.Class.property hops:
+// every reference property must list the next class among its targets, and
+// the path must end on a non-reference property. Fetched class schemas are
+// cached in schemas so repeated hops only cost one request per class.
+func (s *MCPServer) validatePropertyPath(ctx context.Context, conn *WeaviateConnection, collection, path string,
+	schemas map[string]*models.Class,
+) error {
+	parts := strings.Split(path, ".")
+	class := collection
+	for i := 0; i < len(parts); i += 2 {
+		classSchema, ok := schemas[class]
+		if !ok {
+			var err error
+			classSchema, err = conn.GetClassSchema(ctx, class)
+			if err != nil {
+				return err
+			}
+			schemas[class] = classSchema
+		}
+
+		var prop *models.Property
+		for _, p := range classSchema.Properties {
+			if p.Name == parts[i] {
+				prop = p
+				break
+			}
+		}
+		if prop == nil {
+			return &propertyPathError{fmt.Sprintf("property '%s' does not exist in collection '%s'", parts[i], class)}
+		}
+		if s.currentConfig().IsPropertyHidden(class, prop.Name) {
+			return &propertyPathError{fmt.Sprintf("property '%s' of collection '%s' is hidden by this server", parts[i], class)}
+		}
+
+		targets := referenceTargets(prop)
+		if i == len(parts)-1 {
+			if len(targets) > 0 {
+				return &propertyPathError{fmt.Sprintf(
+					"property '%s' of collection '%s' is a cross-reference; select referenced fields with '%s.<Collection>.<property>' (targets: %s)",
+					parts[i], class, strings.Join(parts[:i+1], "."), strings.Join(targets, ", "))}
+			}
+			return nil
+		}
+		if len(targets) == 0 {
+			return &propertyPathError{fmt.Sprintf("property '%s' of collection '%s' is not a cross-reference", parts[i], class)}
+		}
+		if i+2 >= len(parts) {
+			return &propertyPathError{fmt.Sprintf("reference path '%s' must end with a property of '%s'", path, parts[i+1])}
+		}
+		next := parts[i+1]
+		if err := s.checkCollection(ctx, next); err != nil {
+			return &propertyPathError{err.Error()}
+		}
+		found := false
+		for _, target := range targets {
+			if target == next {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &propertyPathError{fmt.Sprintf("property '%s' of collection '%s' does not reference '%s' (targets: %s)",
+				parts[i], class, next, strings.Join(targets, ", "))}
+		}
+		class = next
+	}
+	return nil
 }
 
-func (s *MCPServer) parseTargetCollection(req mcp.CallToolRequest) string {
+// parseTargetCollection returns the collection argument, or the default
+// collection without one, and an error if that collection may not be
+// accessed.
+func (s *MCPServer) parseTargetCollection(ctx context.Context, req mcp.CallToolRequest) (string, error) {
 	var (
-		targetCol = s.defaultCollection
+		targetCol = s.currentConfig().DefaultCollection
 	)
 	args := req.GetArguments()
 	col, ok := args["collection"].(string)
 	if ok {
 		targetCol = col
 	}
-	return targetCol
+	return targetCol, s.checkCollection(ctx, targetCol)
 }
 
 // Prompt handlers
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// resourceMetadataPath is where the protected resource metadata of RFC
+	// 9728 is served, optionally followed by the path of the resource.
+	resourceMetadataPath = "/.well-known/oauth-protected-resource"
+	// tokenLeeway is the clock skew tolerated when checking exp and nbf.
+	tokenLeeway = time.Minute
+	// jwksMaxAge is how long fetched signing keys are used before they are
+	// fetched again.
+	jwksMaxAge = time.Hour
+	// jwksMinRefresh is the least time between two fetches, so tokens with
+	// unknown key IDs cannot make the server hammer the issuer.
+	jwksMinRefresh = 30 * time.Second
+)
+
+// errInsufficientScope is returned for valid tokens that grant none of the
+// configured scopes.
+var errInsufficientScope = errors.New("token grants no scope of this server")
+
+var jwksClient = &http.Client{Timeout: 10 * time.Second}
+
+func (o *OAuthConfig) enabled() bool {
+	return o.Issuer != ""
+}
+
+func (o *OAuthConfig) validate() error {
+	if !o.enabled() {
+		if o.Resource != "" || o.Audience != "" || o.JWKSURL != "" {
+			return fmt.Errorf("oauth: issuer is required")
+		}
+		return nil
+	}
+	if u, err := url.Parse(o.Resource); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("oauth: resource must be the absolute URL of the MCP endpoint: %q", o.Resource)
+	}
+	if u, err := url.Parse(o.JWKSURL); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("oauth: invalid jwks_url: %q", o.JWKSURL)
+	}
+	for _, tokenScope := range sortedKeys(o.Scopes) {
+		if _, ok := scopeNames[o.Scopes[tokenScope]]; !ok {
+			return fmt.Errorf("oauth: scope %s maps to %s, must be 'read', 'write' or 'admin'", tokenScope, o.Scopes[tokenScope])
+		}
+	}
+	return nil
+}
+
+func (o *OAuthConfig) audience() string {
+	if o.Audience != "" {
+		return o.Audience
+	}
+	return o.Resource
+}
+
+func (o *OAuthConfig) scopeClaim() string {
+	if o.ScopeClaim != "" {
+		return o.ScopeClaim
+	}
+	return "scope"
+}
+
+// scopeMap returns the token scopes that grant a scope of this server.
+func (o *OAuthConfig) scopeMap() map[string]string {
+	if len(o.Scopes) > 0 {
+		return o.Scopes
+	}
+	return map[string]string{"read": "read", "write": "write", "admin": "admin"}
+}
+
+// resourceMetadataURL returns where clients find the protected resource
+// metadata: the well-known path is inserted between the host and the path of
+// the resource, as RFC 9728 describes.
+func (o *OAuthConfig) resourceMetadataURL() string {
+	u, err := url.Parse(o.Resource)
+	if err != nil {
+		return ""
+	}
+	u.Path = resourceMetadataPath + strings.TrimSuffix(u.Path, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return u.String()
+}
+
+// protectedResourceMetadata is the document served at resourceMetadataPath.
+type protectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers"`
+	ScopesSupported        []string `json:"scopes_supported"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+}
+
+func (s *MCPServer) handleResourceMetadata(w http.ResponseWriter, r *http.Request) {
+	config := s.currentConfig().OAuth
+	if !config.enabled() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(protectedResourceMetadata{
+		Resource:               config.Resource,
+		AuthorizationServers:   []string{config.Issuer},
+		ScopesSupported:        sortedKeys(config.scopeMap()),
+		BearerMethodsSupported: []string{"header"},
+	})
+}
+
+// verifyAccessToken checks the signature and claims of a JWT access token
+// and returns the caller it authenticates.
+func (s *MCPServer) verifyAccessToken(ctx context.Context, config OAuthConfig, token string) (caller, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return caller{}, fmt.Errorf("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return caller{}, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return caller{}, fmt.Errorf("malformed token signature: %w", err)
+	}
+	key, err := s.jwks.key(ctx, config.JWKSURL, header.Kid)
+	if err != nil {
+		return caller{}, err
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return caller{}, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return caller{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss != config.Issuer {
+		return caller{}, fmt.Errorf("token issued by %q, not %q", iss, config.Issuer)
+	}
+	if !slices.Contains(claimStrings(claims["aud"]), config.audience()) {
+		return caller{}, fmt.Errorf("token audience does not include %q", config.audience())
+	}
+	now := time.Now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return caller{}, fmt.Errorf("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(tokenLeeway)) {
+		return caller{}, fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0).Add(-tokenLeeway)) {
+		return caller{}, fmt.Errorf("token not valid yet")
+	}
+
+	c := caller{Name: "token"}
+	for _, claim := range []string{"sub", "client_id"} {
+		if name, ok := claims[claim].(string); ok && name != "" {
+			c.Name = name
+			break
+		}
+	}
+	granted := false
+	scopes := config.scopeMap()
+	for _, tokenScope := range claimStrings(claims[config.scopeClaim()]) {
+		if scope, ok := scopes[tokenScope]; ok && (!granted || scopeNames[scope] > c.Scope) {
+			c.Scope, granted = scopeNames[scope], true
+		}
+	}
+	if !granted {
+		return c, errInsufficientScope
+	}
+	if config.CollectionsClaim != "" {
+		if value, ok := claims[config.CollectionsClaim]; ok {
+			c.Collections = append([]string{}, claimStrings(value)...)
+		}
+	}
+	return c, nil
+}
+
+// claimStrings returns the strings of a claim given as a list or as a
+// string of values separated by spaces or commas.
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// verifySignature checks a JWS signature made with alg. Only asymmetric
+// algorithms are accepted, so a token can never be signed with a public key
+// or not at all.
+func verifySignature(alg string, key crypto.PublicKey, input, signature []byte) error {
+	var hash crypto.Hash
+	switch alg[min(2, len(alg)):] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	}
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write(input)
+		digest = h.Sum(nil)
+	}
+
+	invalid := fmt.Errorf("invalid token signature")
+	switch {
+	case (strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")) && hash != 0:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key does not match algorithm %s", alg)
+		}
+		var err error
+		if alg[0] == 'R' {
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(pub, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		if err != nil {
+			return invalid
+		}
+	case strings.HasPrefix(alg, "ES") && hash != 0:
+		pub, ok := key.(*ecdsa.PublicKey)
+		curves := map[crypto.Hash]elliptic.Curve{crypto.SHA256: elliptic.P256(), crypto.SHA384: elliptic.P384(), crypto.SHA512: elliptic.P521()}
+		if !ok || pub.Curve != curves[hash] {
+			return fmt.Errorf("key does not match algorithm %s", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return invalid
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return invalid
+		}
+	case alg == "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("key does not match algorithm %s", alg)
+		}
+		if !ed25519.Verify(pub, input, signature) {
+			return invalid
+		}
+	default:
+		return fmt.Errorf("unsupported token algorithm %q", alg)
+	}
+	return nil
+}
+
+// keySet caches the signing keys published at a JWKS URL by key ID.
+type keySet struct {
+	mu   sync.Mutex
+	url  string
+	keys map[string]crypto.PublicKey
+	// fetched is when keys were last fetched, and attempted when a fetch was
+	// last tried, successful or not.
+	fetched   time.Time
+	attempted time.Time
+	// refresh is closed when the fetch in progress ends and is nil when none
+	// is; fetchErr is the error of the last fetch.
+	refresh  chan struct{}
+	fetchErr error
+}
+
+// key returns the signing key with ID kid published at jwksURL, fetching the
+// keys again when kid is unknown or they are older than jwksMaxAge, but at
+// most once per jwksMinRefresh whether or not the last fetch succeeded. A
+// token without kid may use the key of a set holding just one.
+//
+// The fetch runs without holding ks.mu and independently of ctx, so cached
+// keys stay available while it runs and a caller giving up does not fail it
+// for the others. Callers whose key is still cached do not wait for it.
+func (ks *keySet) key(ctx context.Context, jwksURL, kid string) (crypto.PublicKey, error) {
+	ks.mu.Lock()
+	if ks.url != jwksURL {
+		ks.url, ks.keys, ks.fetched, ks.attempted, ks.fetchErr = jwksURL, nil, time.Time{}, time.Time{}, nil
+		ks.refresh = nil
+	}
+	key, ok := ks.lookup(kid)
+	if (!ok || time.Since(ks.fetched) > jwksMaxAge) && ks.refresh == nil && time.Since(ks.attempted) > jwksMinRefresh {
+		ks.attempted = time.Now()
+		ks.refresh = make(chan struct{})
+		go ks.fetch(jwksURL, ks.refresh)
+	}
+	done := ks.refresh
+	ks.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	if done != nil && ks.fetchErr != nil {
+		return nil, fmt.Errorf("fetch signing keys: %w", ks.fetchErr)
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetch fetches the keys at jwksURL into ks and closes done when it ends.
+func (ks *keySet) fetch(jwksURL string, done chan struct{}) {
+	keys, err := fetchJWKS(context.Background(), jwksURL)
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.url == jwksURL {
+		if err == nil {
+			ks.keys, ks.fetched = keys, time.Now()
+		}
+		ks.fetchErr = err
+	}
+	if ks.refresh == done {
+		ks.refresh = nil
+	}
+	close(done)
+}
+
+func (ks *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey holds the members of a JWK used for RSA, EC and Ed25519
+// signing keys.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchJWKS fetches a JSON Web Key Set and returns its signing keys by key
+// ID, skipping keys of types it does not support.
+func fetchJWKS(ctx context.Context, jwksURL string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := jwksClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", jwksURL, resp.Status)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", jwksURL, err)
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil || len(b) == 0 {
+			return nil, fmt.Errorf("invalid key member")
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[jwk.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", jwk.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		b, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || jwk.Crv != "Ed25519" || len(b) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("unsupported OKP key")
+		}
+		return ed25519.PublicKey(b), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+	}
+}
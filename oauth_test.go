@@ -0,0 +1,541 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testResource = "https://mcp.example.com/mcp"
+)
+
+// testSigner is a signing key published by testJWKS.
+type testSigner struct {
+	kid string
+	alg string
+	key crypto.Signer
+}
+
+func newRSASigner(t *testing.T, kid, alg string) testSigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testSigner{kid: kid, alg: alg, key: key}
+}
+
+func newECSigner(t *testing.T, kid, alg string, curve elliptic.Curve) testSigner {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testSigner{kid: kid, alg: alg, key: key}
+}
+
+func newEd25519Signer(t *testing.T, kid string) testSigner {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testSigner{kid: kid, alg: "EdDSA", key: key}
+}
+
+func (s testSigner) jwk() jsonWebKey {
+	enc := base64.RawURLEncoding.EncodeToString
+	switch pub := s.key.Public().(type) {
+	case *rsa.PublicKey:
+		return jsonWebKey{Kty: "RSA", Kid: s.kid, Use: "sig", N: enc(pub.N.Bytes()), E: enc(big.NewInt(int64(pub.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		return jsonWebKey{Kty: "EC", Kid: s.kid, Use: "sig", Crv: pub.Curve.Params().Name, X: enc(pub.X.FillBytes(make([]byte, size))), Y: enc(pub.Y.FillBytes(make([]byte, size)))}
+	case ed25519.PublicKey:
+		return jsonWebKey{Kty: "OKP", Kid: s.kid, Use: "sig", Crv: "Ed25519", X: enc(pub)}
+	default:
+		panic("unsupported key type")
+	}
+}
+
+// sign returns a JWT with claims, signed with the algorithm of s.
+func (s testSigner) sign(t *testing.T, claims map[string]interface{}) string {
+	t.Helper()
+	input := encodeSegment(t, map[string]string{"alg": s.alg, "kid": s.kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+
+	var hash crypto.Hash
+	switch s.alg[len(s.alg)-3:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	}
+	digest := []byte(input)
+	var opts crypto.SignerOpts = crypto.Hash(0)
+	if hash != 0 {
+		h := hash.New()
+		h.Write(digest)
+		digest, opts = h.Sum(nil), hash
+	}
+	if s.alg[:2] == "PS" {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+	}
+
+	var signature []byte
+	var err error
+	if key, ok := s.key.(*ecdsa.PrivateKey); ok {
+		// JWS uses the fixed-size r||s encoding rather than ASN.1
+		var r, sig *big.Int
+		r, sig, err = ecdsa.Sign(rand.Reader, key, digest)
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), sig.FillBytes(make([]byte, size))...)
+	} else {
+		signature, err = s.key.Sign(rand.Reader, digest, opts)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// testJWKS serves the public keys of its signers as a JSON Web Key Set and
+// counts the requests it receives. While hold is set, requests wait until it
+// is closed.
+type testJWKS struct {
+	*httptest.Server
+	mu       sync.Mutex
+	signers  []testSigner
+	fail     bool
+	hold     chan struct{}
+	requests atomic.Int32
+}
+
+func newTestJWKS(t *testing.T, signers ...testSigner) *testJWKS {
+	t.Helper()
+	jwks := &testJWKS{signers: signers}
+	jwks.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jwks.requests.Add(1)
+		jwks.mu.Lock()
+		hold := jwks.hold
+		jwks.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+		jwks.mu.Lock()
+		defer jwks.mu.Unlock()
+		if jwks.fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		keys := make([]jsonWebKey, len(jwks.signers))
+		for i, signer := range jwks.signers {
+			keys[i] = signer.jwk()
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	t.Cleanup(jwks.Close)
+	return jwks
+}
+
+func (jwks *testJWKS) set(fail bool, signers ...testSigner) {
+	jwks.mu.Lock()
+	defer jwks.mu.Unlock()
+	jwks.fail, jwks.signers = fail, signers
+}
+
+func (jwks *testJWKS) config() OAuthConfig {
+	return OAuthConfig{Issuer: testIssuer, Resource: testResource, JWKSURL: jwks.URL}
+}
+
+// validClaims returns the claims of a token accepted by jwks.config().
+func validClaims() map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":   testIssuer,
+		"aud":   testResource,
+		"sub":   "alice",
+		"scope": "read",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+func withClaims(overrides map[string]interface{}) map[string]interface{} {
+	claims := validClaims()
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+// allowJWKSRefetch lets the next token with an unknown key ID fetch the keys
+// again, as if jwksMinRefresh had passed.
+func allowJWKSRefetch(s *MCPServer) {
+	s.jwks.mu.Lock()
+	defer s.jwks.mu.Unlock()
+	s.jwks.attempted = time.Now().Add(-jwksMinRefresh - time.Second)
+}
+
+func TestVerifyAccessTokenAlgorithms(t *testing.T) {
+	signers := []testSigner{
+		newRSASigner(t, "rs256", "RS256"),
+		newRSASigner(t, "ps384", "PS384"),
+		newECSigner(t, "es256", "ES256", elliptic.P256()),
+		newECSigner(t, "es384", "ES384", elliptic.P384()),
+		newEd25519Signer(t, "ed25519"),
+	}
+	jwks := newTestJWKS(t, signers...)
+	s := &MCPServer{}
+	for _, signer := range signers {
+		t.Run(signer.alg, func(t *testing.T) {
+			c, err := s.verifyAccessToken(context.Background(), jwks.config(), signer.sign(t, validClaims()))
+			if err != nil {
+				t.Fatalf("valid token rejected: %v", err)
+			}
+			if c.Name != "alice" || c.Scope != toolRead || c.Collections != nil {
+				t.Errorf("caller = %+v, want alice with the read scope", c)
+			}
+		})
+	}
+	if n := jwks.requests.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+}
+
+func TestVerifyAccessTokenClaims(t *testing.T) {
+	signer := newRSASigner(t, "key", "RS256")
+	jwks := newTestJWKS(t, signer)
+	s := &MCPServer{}
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		valid  bool
+	}{
+		{"audience list", withClaims(map[string]interface{}{"aud": []string{"other", testResource}}), true},
+		{"expired within leeway", withClaims(map[string]interface{}{"exp": now.Add(-tokenLeeway / 2).Unix()}), true},
+		{"client_id names the caller", withClaims(map[string]interface{}{"sub": nil, "client_id": "agent"}), true},
+		{"wrong issuer", withClaims(map[string]interface{}{"iss": "https://evil.example.com"}), false},
+		{"wrong audience", withClaims(map[string]interface{}{"aud": "https://other.example.com/mcp"}), false},
+		{"no audience", withClaims(map[string]interface{}{"aud": nil}), false},
+		{"expired", withClaims(map[string]interface{}{"exp": now.Add(-2 * tokenLeeway).Unix()}), false},
+		{"no expiry", withClaims(map[string]interface{}{"exp": nil}), false},
+		{"not valid yet", withClaims(map[string]interface{}{"nbf": now.Add(2 * tokenLeeway).Unix()}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.verifyAccessToken(context.Background(), jwks.config(), signer.sign(t, tt.claims))
+			if tt.valid && err != nil {
+				t.Errorf("valid token rejected: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("invalid token accepted")
+			}
+		})
+	}
+
+	t.Run("configured audience", func(t *testing.T) {
+		config := jwks.config()
+		config.Audience = "api://mcp"
+		token := signer.sign(t, withClaims(map[string]interface{}{"aud": "api://mcp"}))
+		if _, err := s.verifyAccessToken(context.Background(), config, token); err != nil {
+			t.Errorf("token for the configured audience rejected: %v", err)
+		}
+		if _, err := s.verifyAccessToken(context.Background(), config, signer.sign(t, validClaims())); err == nil {
+			t.Error("token for the resource accepted although another audience is configured")
+		}
+	})
+}
+
+func TestVerifyAccessTokenRejectsUnsignedAndSymmetricTokens(t *testing.T) {
+	signer := newRSASigner(t, "key", "RS256")
+	jwks := newTestJWKS(t, signer)
+	s := &MCPServer{}
+	claims := encodeSegment(t, validClaims())
+
+	unsigned := encodeSegment(t, map[string]string{"alg": "none", "kid": "key"}) + "." + claims + "."
+	if _, err := s.verifyAccessToken(context.Background(), jwks.config(), unsigned); err == nil {
+		t.Error("token with alg none accepted")
+	}
+
+	// An HS256 token keyed with the public key, the classic algorithm
+	// confusion attack
+	input := encodeSegment(t, map[string]string{"alg": "HS256", "kid": "key"}) + "." + claims
+	pub := signer.key.Public().(*rsa.PublicKey)
+	mac := hmac.New(sha256.New, pub.N.Bytes())
+	mac.Write([]byte(input))
+	symmetric := input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if _, err := s.verifyAccessToken(context.Background(), jwks.config(), symmetric); err == nil {
+		t.Error("HS256 token accepted")
+	}
+
+	// A valid signature with the algorithm swapped in the header
+	token := signer.sign(t, validClaims())
+	tampered := encodeSegment(t, map[string]string{"alg": "PS256", "kid": "key"}) + token[len(encodeSegment(t, map[string]string{"alg": "RS256", "kid": "key", "typ": "JWT"})):]
+	if _, err := s.verifyAccessToken(context.Background(), jwks.config(), tampered); err == nil {
+		t.Error("token with a changed algorithm accepted")
+	}
+}
+
+func TestVerifyAccessTokenKeyRotation(t *testing.T) {
+	oldKey := newRSASigner(t, "old", "RS256")
+	newKey := newECSigner(t, "new", "ES256", elliptic.P256())
+	jwks := newTestJWKS(t, oldKey)
+	s := &MCPServer{}
+	ctx := context.Background()
+
+	if _, err := s.verifyAccessToken(ctx, jwks.config(), oldKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("token signed with the published key rejected: %v", err)
+	}
+	jwks.set(false, newKey)
+
+	// The new key ID is unknown, but the keys were fetched too recently
+	if _, err := s.verifyAccessToken(ctx, jwks.config(), newKey.sign(t, validClaims())); err == nil {
+		t.Fatal("token signed with an unfetched key accepted")
+	}
+	if n := jwks.requests.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times within jwksMinRefresh, want 1", n)
+	}
+
+	allowJWKSRefetch(s)
+	if _, err := s.verifyAccessToken(ctx, jwks.config(), newKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("token signed with the rotated key rejected: %v", err)
+	}
+	if n := jwks.requests.Load(); n != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2 after the key rotation", n)
+	}
+	// The keys are fetched once for the new kid, not for every token
+	if _, err := s.verifyAccessToken(ctx, jwks.config(), newKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("second token signed with the rotated key rejected: %v", err)
+	}
+	if _, err := s.verifyAccessToken(ctx, jwks.config(), oldKey.sign(t, validClaims())); err == nil {
+		t.Error("token signed with the retired key accepted")
+	}
+	if n := jwks.requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+func TestVerifyAccessTokenThrottlesFailedFetches(t *testing.T) {
+	signer := newRSASigner(t, "key", "RS256")
+	jwks := newTestJWKS(t, signer)
+	jwks.set(true, signer)
+	s := &MCPServer{}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.verifyAccessToken(ctx, jwks.config(), signer.sign(t, validClaims())); err == nil {
+			t.Fatal("token accepted although the keys could not be fetched")
+		}
+	}
+	if n := jwks.requests.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times after a failure, want 1 within jwksMinRefresh", n)
+	}
+
+	jwks.set(false, signer)
+	allowJWKSRefetch(s)
+	if _, err := s.verifyAccessToken(ctx, jwks.config(), signer.sign(t, validClaims())); err != nil {
+		t.Fatalf("token rejected after the keys became available: %v", err)
+	}
+	if n := jwks.requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+// A slow key fetch neither holds up tokens signed with cached keys nor fails
+// when the caller that started it gives up.
+func TestVerifyAccessTokenSlowJWKSFetch(t *testing.T) {
+	oldKey := newRSASigner(t, "old", "RS256")
+	newKey := newECSigner(t, "new", "ES256", elliptic.P256())
+	jwks := newTestJWKS(t, oldKey)
+	s := &MCPServer{}
+
+	if _, err := s.verifyAccessToken(context.Background(), jwks.config(), oldKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("token signed with the published key rejected: %v", err)
+	}
+	hold := make(chan struct{})
+	jwks.mu.Lock()
+	jwks.signers, jwks.hold = []testSigner{oldKey, newKey}, hold
+	jwks.mu.Unlock()
+	allowJWKSRefetch(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := s.verifyAccessToken(ctx, jwks.config(), newKey.sign(t, validClaims()))
+		errc <- err
+	}()
+	for jwks.requests.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := s.verifyAccessToken(context.Background(), jwks.config(), oldKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("token signed with a cached key rejected during a fetch: %v", err)
+	}
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled caller: err = %v, want context.Canceled", err)
+	}
+
+	close(hold)
+	if _, err := s.verifyAccessToken(context.Background(), jwks.config(), newKey.sign(t, validClaims())); err != nil {
+		t.Fatalf("token signed with the new key rejected after the fetch: %v", err)
+	}
+	if n := jwks.requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+func TestVerifyAccessTokenScopes(t *testing.T) {
+	signer := newEd25519Signer(t, "key")
+	jwks := newTestJWKS(t, signer)
+	s := &MCPServer{}
+
+	tests := []struct {
+		name    string
+		config  func(*OAuthConfig)
+		claims  map[string]interface{}
+		want    toolAccess
+		noScope bool
+	}{
+		{name: "read", claims: withClaims(map[string]interface{}{"scope": "read"}), want: toolRead},
+		{name: "highest scope applies", claims: withClaims(map[string]interface{}{"scope": "read admin write"}), want: toolAdmin},
+		{name: "unknown scopes", claims: withClaims(map[string]interface{}{"scope": "openid profile"}), noScope: true},
+		{name: "no scope claim", claims: withClaims(map[string]interface{}{"scope": nil}), noScope: true},
+		{
+			name: "mapped scopes in a list claim",
+			config: func(c *OAuthConfig) {
+				c.ScopeClaim, c.Scopes = "scp", map[string]string{"mcp.query": "read", "mcp.manage": "admin"}
+			},
+			claims: withClaims(map[string]interface{}{"scp": []string{"mcp.query", "mcp.manage"}}),
+			want:   toolAdmin,
+		},
+		{
+			name:    "unmapped default names",
+			config:  func(c *OAuthConfig) { c.Scopes = map[string]string{"mcp.query": "read"} },
+			claims:  withClaims(map[string]interface{}{"scope": "admin"}),
+			noScope: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := jwks.config()
+			if tt.config != nil {
+				tt.config(&config)
+			}
+			c, err := s.verifyAccessToken(context.Background(), config, signer.sign(t, tt.claims))
+			if tt.noScope {
+				if !errors.Is(err, errInsufficientScope) {
+					t.Errorf("err = %v, want errInsufficientScope", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("valid token rejected: %v", err)
+			}
+			if c.Scope != tt.want {
+				t.Errorf("scope = %s, want %s", c.Scope, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessTokenToolAccess(t *testing.T) {
+	signer := newECSigner(t, "key", "ES256", elliptic.P256())
+	jwks := newTestJWKS(t, signer)
+	s := &MCPServer{config: &Config{}, logger: &Logger{output: io.Discard}}
+	s.toolAccess.Store("weaviate-query", toolRead)
+	s.toolAccess.Store("weaviate-reference-add", toolWrite)
+	s.toolAccess.Store("weaviate-delete-collection", toolAdmin)
+	tools := []mcp.Tool{
+		mcp.NewTool("weaviate-query"),
+		mcp.NewTool("weaviate-reference-add"),
+		mcp.NewTool("weaviate-delete-collection"),
+		mcp.NewTool("unregistered"),
+	}
+
+	config := jwks.config()
+	config.CollectionsClaim = "collections"
+	token := signer.sign(t, withClaims(map[string]interface{}{"scope": "write", "collections": "Article Public*"}))
+	c, err := s.verifyAccessToken(context.Background(), config, token)
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), callerKey{}, c)
+
+	var visible []string
+	for _, tool := range s.filterToolsByScope(ctx, tools) {
+		visible = append(visible, tool.Name)
+	}
+	if want := []string{"weaviate-query", "weaviate-reference-add"}; !slices.Equal(visible, want) {
+		t.Errorf("visible tools = %v, want %v", visible, want)
+	}
+
+	called := false
+	handler := s.scopeMiddleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "weaviate-delete-collection"
+	res, err := handler(ctx, req)
+	if err != nil || !res.IsError || called {
+		t.Errorf("admin tool called with the write scope: result %+v, err %v", res, err)
+	}
+	req.Params.Name = "weaviate-reference-add"
+	if res, err := handler(ctx, req); err != nil || res.IsError || !called {
+		t.Errorf("write tool rejected with the write scope: result %+v, err %v", res, err)
+	}
+
+	for collection, allowed := range map[string]bool{"Article": true, "PublicDocs": true, "Customer": false} {
+		if err := s.checkCollection(ctx, collection); (err == nil) != allowed {
+			t.Errorf("checkCollection(%s) = %v, want allowed %v", collection, err, allowed)
+		}
+	}
+	if err := s.checkCollection(context.Background(), "Customer"); err != nil {
+		t.Errorf("the token's collections restricted another request: %v", err)
+	}
+
+	// Tokens without the claim are only limited by the server's lists
+	token = signer.sign(t, withClaims(map[string]interface{}{"scope": "read"}))
+	if c, err := s.verifyAccessToken(context.Background(), config, token); err != nil || c.Collections != nil {
+		t.Errorf("token without the collections claim: caller %+v, err %v", c, err)
+	}
+}
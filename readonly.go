@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrReadOnly is returned by every call that would change objects, schema,
+// tenants or backups through a read-only connection.
+var ErrReadOnly = errors.New("connection is read-only")
+
+// checkWritable returns an error wrapping ErrReadOnly if conn is read-only.
+// Every mutating WeaviateConnection method calls it first, so the caller gets
+// an error naming the operation.
+func (conn *WeaviateConnection) checkWritable(op string) error {
+	if conn.readOnly {
+		return fmt.Errorf("%s on connection %s: %w", op, conn.name, ErrReadOnly)
+	}
+	return nil
+}
+
+// readOnlyTransport backs up checkWritable for write paths that do not call
+// it: it refuses to send any request that could change Weaviate. The client
+// is created without gRPC, so every request goes through it.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+func (t readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadOnlyRequest(req) {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrReadOnly)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// isReadOnlyRequest reports whether req only reads from Weaviate: a GET or
+// HEAD, or a GraphQL query, since Weaviate's GraphQL API has no mutations.
+func isReadOnlyRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return strings.HasPrefix(req.URL.Path, "/v1/graphql")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// fakeWeaviate answers every request with an empty JSON object, or an empty
+// array for tenant lists, and records the requests it receives in reads or,
+// if they could have changed Weaviate, in writes.
+type fakeWeaviate struct {
+	*httptest.Server
+	mu     sync.Mutex
+	reads  []string
+	writes []string
+}
+
+func newFakeWeaviate(t *testing.T) *fakeWeaviate {
+	t.Helper()
+	fake := &fakeWeaviate{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		if isReadOnlyRequest(r) {
+			fake.reads = append(fake.reads, r.Method+" "+r.URL.Path)
+		} else {
+			fake.writes = append(fake.writes, r.Method+" "+r.URL.Path)
+		}
+		fake.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/tenants") {
+			io.WriteString(w, "[]")
+			return
+		}
+		io.WriteString(w, "{}")
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+// checkNoWrites fails t if any write request reached the server.
+func (fake *fakeWeaviate) checkNoWrites(t *testing.T) {
+	t.Helper()
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.writes) > 0 {
+		t.Errorf("write requests reached Weaviate: %v", fake.writes)
+	}
+}
+
+// checkRead fails t unless a request for path reached the server.
+func (fake *fakeWeaviate) checkRead(t *testing.T, method, path string) {
+	t.Helper()
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, read := range fake.reads {
+		if read == method+" "+path {
+			return
+		}
+	}
+	t.Errorf("%s %s did not reach Weaviate; reads: %v", method, path, fake.reads)
+}
+
+func newReadOnlyConnection(t *testing.T, fake *fakeWeaviate) *WeaviateConnection {
+	t.Helper()
+	u, err := url.Parse(fake.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := ConnectionConfig{Host: u.Host, Scheme: u.Scheme, ReadOnly: true}
+	conn, err := NewWeaviateConnection("test", config, &Logger{output: io.Discard})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	return conn
+}
+
+func TestReadOnlyConnectionRejectsWrites(t *testing.T) {
+	fake := newFakeWeaviate(t)
+	conn := newReadOnlyConnection(t, fake)
+	ctx := context.Background()
+	ref := ReferenceSpec{Collection: "Article", ID: "00000000-0000-0000-0000-000000000001", Property: "hasAuthor", TargetCollection: "Author", TargetID: "00000000-0000-0000-0000-000000000002"}
+
+	writes := map[string]func() error{
+		"InsertOne": func() error {
+			_, err := conn.InsertOne(ctx, "Article", map[string]interface{}{"title": "x"}, "", "")
+			return err
+		},
+		"batchInsert": func() error {
+			_, err := conn.batchInsert(ctx, "", 0, &models.Object{Class: "Article"})
+			return err
+		},
+		"AddReference":     func() error { return conn.AddReference(ctx, ref) },
+		"ReplaceReference": func() error { return conn.ReplaceReference(ctx, ref) },
+		"DeleteReference":  func() error { return conn.DeleteReference(ctx, ref) },
+		"CreateCollection": func() error { return conn.CreateCollection(ctx, &models.Class{Class: "Scratch"}) },
+		"AddProperty": func() error {
+			return conn.AddProperty(ctx, "Article", &models.Property{Name: "summary", DataType: []string{"text"}})
+		},
+		"DeleteCollection": func() error { return conn.DeleteCollection(ctx, "Article") },
+		"CreateTenants":    func() error { return conn.CreateTenants(ctx, "Article", models.Tenant{Name: "acme"}) },
+		"UpdateTenants": func() error {
+			return conn.UpdateTenants(ctx, "Article", models.Tenant{Name: "acme", ActivityStatus: models.TenantActivityStatusINACTIVE})
+		},
+		"DeleteTenants": func() error { return conn.DeleteTenants(ctx, "Article", "acme") },
+		"CreateBackup": func() error {
+			_, err := conn.CreateBackup(ctx, "nightly", []string{"Article"})
+			return err
+		},
+		"RestoreBackup": func() error {
+			_, err := conn.RestoreBackup(ctx, "nightly", []string{"Article"})
+			return err
+		},
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			if err := write(); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("err = %v, want ErrReadOnly", err)
+			}
+		})
+	}
+	fake.checkNoWrites(t)
+}
+
+func TestReadOnlyConnectionAllowsReads(t *testing.T) {
+	fake := newFakeWeaviate(t)
+	conn := newReadOnlyConnection(t, fake)
+	ctx := context.Background()
+
+	if _, err := conn.GetSchema(ctx); err != nil {
+		t.Errorf("GetSchema: %v", err)
+	}
+	fake.checkRead(t, http.MethodGet, "/v1/schema")
+	if _, err := conn.ListTenants(ctx, "Article"); err != nil {
+		t.Errorf("ListTenants: %v", err)
+	}
+	fake.checkRead(t, http.MethodGet, "/v1/schema/Article/tenants")
+	if _, err := conn.Query(ctx, "Article", "weaviate", []string{"title"}, SearchOptions{Limit: 1}); err != nil && strings.Contains(err.Error(), ErrReadOnly.Error()) {
+		t.Errorf("Query: %v", err)
+	}
+	fake.checkRead(t, http.MethodPost, "/v1/graphql")
+	fake.checkNoWrites(t)
+}
+
+// The transport stops writes that bypass checkWritable, such as direct use
+// of the Weaviate client.
+func TestReadOnlyConnectionClientBypass(t *testing.T) {
+	fake := newFakeWeaviate(t)
+	conn := newReadOnlyConnection(t, fake)
+	ctx := context.Background()
+
+	if _, err := conn.client.Data().Creator().WithClassName("Article").Do(ctx); err == nil || !strings.Contains(err.Error(), ErrReadOnly.Error()) {
+		t.Errorf("object create: err = %v, want ErrReadOnly", err)
+	}
+	if _, err := conn.client.Batch().ObjectsBatcher().WithObjects(&models.Object{Class: "Article"}).Do(ctx); err == nil || !strings.Contains(err.Error(), ErrReadOnly.Error()) {
+		t.Errorf("batch: err = %v, want ErrReadOnly", err)
+	}
+	if err := conn.client.Schema().ClassDeleter().WithClassName("Article").Do(ctx); err == nil || !strings.Contains(err.Error(), ErrReadOnly.Error()) {
+		t.Errorf("class delete: err = %v, want ErrReadOnly", err)
+	}
+	fake.checkNoWrites(t)
+}
+
+func TestReadOnlyTransport(t *testing.T) {
+	fake := newFakeWeaviate(t)
+	transport := readOnlyTransport{next: http.DefaultTransport}
+
+	tests := []struct {
+		method  string
+		path    string
+		allowed bool
+	}{
+		{http.MethodGet, "/v1/schema", true},
+		{http.MethodGet, "/v1/objects/Article/00000000-0000-0000-0000-000000000001", true},
+		{http.MethodHead, "/v1/objects/Article/00000000-0000-0000-0000-000000000001", true},
+		{http.MethodPost, "/v1/graphql", true},
+		{http.MethodPost, "/v1/graphql/batch", true},
+		{http.MethodPost, "/v1/batch/objects", false},
+		{http.MethodPost, "/v1/batch/references", false},
+		{http.MethodDelete, "/v1/batch/objects", false},
+		{http.MethodPost, "/v1/objects", false},
+		{http.MethodPut, "/v1/objects/Article/00000000-0000-0000-0000-000000000001", false},
+		{http.MethodPatch, "/v1/objects/Article/00000000-0000-0000-0000-000000000001", false},
+		{http.MethodDelete, "/v1/objects/Article/00000000-0000-0000-0000-000000000001", false},
+		{http.MethodPost, "/v1/schema", false},
+		{http.MethodPut, "/v1/schema/Article", false},
+		{http.MethodPatch, "/v1/schema/Article", false},
+		{http.MethodDelete, "/v1/schema/Article", false},
+		{http.MethodPost, "/v1/schema/Article/tenants", false},
+		{http.MethodPost, "/v1/backups/filesystem", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, fake.URL+tt.path, strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("read request rejected: %v", err)
+				}
+				resp.Body.Close()
+				return
+			}
+			if !errors.Is(err, ErrReadOnly) {
+				t.Errorf("err = %v, want ErrReadOnly", err)
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+		})
+	}
+	fake.checkNoWrites(t)
+}
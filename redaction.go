@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// compiledRedaction is a RedactionPolicy ready to be applied to the
+// collections matching its pattern.
+type compiledRedaction struct {
+	collections string
+	hide        map[string]bool
+	masks       map[string][]compiledMask
+}
+
+type compiledMask struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// compileRedaction checks the redaction policies and compiles their patterns.
+func (c *Config) compileRedaction() error {
+	c.redactions = nil
+	for _, collections := range sortedKeys(c.Redaction) {
+		if _, err := path.Match(collections, ""); err != nil {
+			return fmt.Errorf("redaction: invalid collection pattern: %s", collections)
+		}
+		policy := c.Redaction[collections]
+		redaction := compiledRedaction{
+			collections: collections,
+			hide:        make(map[string]bool),
+			masks:       make(map[string][]compiledMask),
+		}
+		for _, prop := range policy.Hide {
+			redaction.hide[prop] = true
+		}
+		for _, rule := range policy.Mask {
+			if rule.Property == "" {
+				return fmt.Errorf("redaction %s: mask rule without a property", collections)
+			}
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return fmt.Errorf("redaction %s: invalid mask pattern for %s: %w", collections, rule.Property, err)
+			}
+			redaction.masks[rule.Property] = append(redaction.masks[rule.Property], compiledMask{pattern, rule.Replacement})
+		}
+		c.redactions = append(c.redactions, redaction)
+	}
+	return nil
+}
+
+// redactionsFor returns the policies that apply to collection.
+func (c *Config) redactionsFor(collection string) []compiledRedaction {
+	name := canonicalCollectionName(collection)
+	var redactions []compiledRedaction
+	for _, redaction := range c.redactions {
+		if ok, _ := path.Match(redaction.collections, name); ok {
+			redactions = append(redactions, redaction)
+		}
+	}
+	return redactions
+}
+
+// IsPropertyHidden reports whether a redaction policy hides property of
+// collection.
+func (c *Config) IsPropertyHidden(collection, property string) bool {
+	for _, redaction := range c.redactionsFor(collection) {
+		if redaction.hide[property] {
+			return true
+		}
+	}
+	return false
+}
+
+// maskValue applies the mask rules for property of collection to every
+// string in value, at any depth.
+func (c *Config) maskValue(collection, property string, value interface{}) interface{} {
+	var masks []compiledMask
+	for _, redaction := range c.redactionsFor(collection) {
+		masks = append(masks, redaction.masks[property]...)
+	}
+	if len(masks) == 0 {
+		return value
+	}
+	return mapStrings(value, func(s string) string {
+		for _, mask := range masks {
+			s = mask.pattern.ReplaceAllString(s, mask.replacement)
+		}
+		return s
+	})
+}
+
+// redactProperties returns a copy of the property values of an object of
+// collection without hidden properties and with masks applied.
+func (c *Config) redactProperties(collection string, props map[string]interface{}) map[string]interface{} {
+	return c.redactObject([]string{collection}, "", props, nil)
+}
+
+// redactClass returns class without its hidden properties, including nested
+// ones, copying it only if it has any.
+func (c *Config) redactClass(class *models.Class) *models.Class {
+	changed := false
+	visible := make([]*models.Property, 0, len(class.Properties))
+	for _, prop := range class.Properties {
+		if c.IsPropertyHidden(class.Class, prop.Name) {
+			changed = true
+			continue
+		}
+		if nested, ok := c.redactNestedProperties(class.Class, prop.Name+".", prop.NestedProperties); ok {
+			redacted := *prop
+			redacted.NestedProperties = nested
+			prop = &redacted
+			changed = true
+		}
+		visible = append(visible, prop)
+	}
+	if !changed {
+		return class
+	}
+	redacted := *class
+	redacted.Properties = visible
+	return &redacted
+}
+
+// redactNestedProperties returns the nested properties of an object property
+// at the dotted path prefix without the hidden ones, and whether any were
+// removed.
+func (c *Config) redactNestedProperties(collection, prefix string, props []*models.NestedProperty) ([]*models.NestedProperty, bool) {
+	changed := false
+	visible := make([]*models.NestedProperty, 0, len(props))
+	for _, prop := range props {
+		if c.IsPropertyHidden(collection, prefix+prop.Name) {
+			changed = true
+			continue
+		}
+		if nested, ok := c.redactNestedProperties(collection, prefix+prop.Name+".", prop.NestedProperties); ok {
+			redacted := *prop
+			redacted.NestedProperties = nested
+			prop = &redacted
+			changed = true
+		}
+		visible = append(visible, prop)
+	}
+	return visible, changed
+}
+
+// referenceNode records, for a reference property selected by dotted
+// targetProperties paths, the collections selected through it and the
+// references selected below it.
+type referenceNode struct {
+	collections []string
+	children    map[string]*referenceNode
+}
+
+// referenceTree builds the tree of reference properties selected by
+// targetProps, e.g. hasAuthor -> [Author] for "hasAuthor.Author.name".
+func referenceTree(targetProps []string) map[string]*referenceNode {
+	root := make(map[string]*referenceNode)
+	for _, prop := range targetProps {
+		parts := strings.Split(prop, ".")
+		level := root
+		for i := 0; i+2 < len(parts); i += 2 {
+			node, ok := level[parts[i]]
+			if !ok {
+				node = &referenceNode{children: make(map[string]*referenceNode)}
+				level[parts[i]] = node
+			}
+			if !slices.Contains(node.collections, parts[i+1]) {
+				node.collections = append(node.collections, parts[i+1])
+			}
+			level = node.children
+		}
+	}
+	return root
+}
+
+// redactSearchResult applies the redaction policies to the objects of a
+// search on collection that selected targetProps, replacing them with
+// redacted copies.
+func (c *Config) redactSearchResult(collection string, targetProps []string, res *models.GraphQLResponse) {
+	if len(c.redactions) == 0 || res == nil {
+		return
+	}
+	get, _ := res.Data["Get"].(map[string]interface{})
+	name := canonicalCollectionName(collection)
+	objs, _ := get[name].([]interface{})
+	refs := referenceTree(targetProps)
+	for i, obj := range objs {
+		if props, ok := obj.(map[string]interface{}); ok {
+			objs[i] = c.redactObject([]string{collection}, "", props, refs)
+		}
+	}
+}
+
+// redactObject returns a copy of props, the properties of an object of one
+// of collections, without hidden properties and with masks applied. prefix is
+// the dotted path of props within a nested object property, and "" for the
+// object itself: policies name nested properties by their path, e.g.
+// address.street. The references in refs are followed into the referenced
+// objects. A search result does not say which collection a referenced object
+// belongs to, so the policies of every collection selected through the
+// reference apply.
+func (c *Config) redactObject(collections []string, prefix string, props map[string]interface{}, refs map[string]*referenceNode) map[string]interface{} {
+	out := make(map[string]interface{}, len(props))
+	for name, value := range props {
+		if prefix == "" && name == "_additional" {
+			out[name] = value
+			continue
+		}
+		path := prefix + name
+		if slices.ContainsFunc(collections, func(collection string) bool { return c.IsPropertyHidden(collection, path) }) {
+			continue
+		}
+		if node, ok := refs[name]; ok {
+			items, _ := value.([]interface{})
+			redacted := make([]interface{}, len(items))
+			for i, item := range items {
+				redacted[i] = item
+				if ref, ok := item.(map[string]interface{}); ok {
+					redacted[i] = c.redactObject(node.collections, "", ref, node.children)
+				}
+			}
+			out[name] = redacted
+			continue
+		}
+		value = c.redactNested(collections, path, value)
+		for _, collection := range collections {
+			value = c.maskValue(collection, path, value)
+		}
+		out[name] = value
+	}
+	return out
+}
+
+// redactNested redacts the value of a nested object or object[] property at
+// path, and returns any other value unchanged.
+func (c *Config) redactNested(collections []string, path string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return c.redactObject(collections, path+".", v, nil)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = c.redactNested(collections, path, item)
+		}
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// referenceTools returns the cross-reference management tools. They all
+// write to Weaviate, so none of them are registered in read-only mode.
+func (s *MCPServer) referenceTools() []server.ServerTool {
+	var tools []server.ServerTool
+
+	add := mcp.NewTool("weaviate-reference-add", referenceToolOptions("Add a cross-reference from a source object to a target object")...)
+	tools = append(tools, s.registerTool(add, s.weaviateReferenceAdd, toolWrite)...)
+
+	replace := mcp.NewTool("weaviate-reference-replace", referenceToolOptions("Replace all cross-references on a source object's reference property with a single reference to the target object")...)
+	tools = append(tools, s.registerTool(replace, s.weaviateReferenceReplace, toolWrite)...)
+
+	del := mcp.NewTool("weaviate-reference-delete", referenceToolOptions("Delete a cross-reference from a source object to a target object")...)
+	tools = append(tools, s.registerTool(del, s.weaviateReferenceDelete, toolWrite)...)
+
+	return tools
+}
+
+func referenceToolOptions(description string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithDescription(description),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the source collection"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"id",
+			mcp.Description("UUID of the source object"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"property",
+			mcp.Description("Name of the reference property on the source collection"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"targetCollection",
+			mcp.Description("Name of the referenced collection; must be one of the reference property's target collections"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"targetId",
+			mcp.Description("UUID of the referenced object"),
+			mcp.Required(),
+		),
+		withTenantOption(),
+		withConsistencyLevelOption(),
+	}
+}
+
+func (s *MCPServer) weaviateReferenceAdd(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.handleReference(ctx, req, "add", (*WeaviateConnection).AddReference)
+}
+
+func (s *MCPServer) weaviateReferenceReplace(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.handleReference(ctx, req, "replace", (*WeaviateConnection).ReplaceReference)
+}
+
+func (s *MCPServer) weaviateReferenceDelete(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.handleReference(ctx, req, "delete", (*WeaviateConnection).DeleteReference)
+}
+
+// handleReference parses and validates the reference arguments shared by the
+// reference tools, then runs op against Weaviate.
+func (s *MCPServer) handleReference(ctx context.Context, req mcp.CallToolRequest, action string,
+	op func(*WeaviateConnection, context.Context, ReferenceSpec) error,
+) (*mcp.CallToolResult, error) {
+	s.logger.Debug("Reference %s called: args=%v", action, req.GetArguments())
+	ref, err := parseReferenceSpec(req)
+	if err != nil {
+		s.logger.Error("Invalid reference %s arguments: %v", action, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if ref.Tenant, err = s.resolveTenant(ctx, conn, ref.Collection, ref.Tenant); err != nil {
+		s.logger.Error("Invalid tenant for reference %s: %v", action, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if msg, err := s.validateReference(ctx, conn, ref); err != nil {
+		s.logger.Error("Failed to get schema for collection %s: %v", ref.Collection, err)
+		return mcp.NewToolResultErrorFromErr("failed to get collection schema", err), nil
+	} else if msg != "" {
+		s.logger.Error("Invalid reference %s: %s", action, msg)
+		return mcp.NewToolResultError(msg), nil
+	}
+	if err := op(conn, ctx, ref); err != nil {
+		s.logger.Error("Reference %s error: %v", action, err)
+		return mcp.NewToolResultErrorFromErr(fmt.Sprintf("failed to %s reference", action), err), nil
+	}
+	s.logger.Info("Reference %s success: %s/%s.%s -> %s/%s", action,
+		ref.Collection, ref.ID, ref.Property, ref.TargetCollection, ref.TargetID)
+	return mcp.NewToolResultText(fmt.Sprintf("Reference %s succeeded: %s/%s.%s -> %s/%s", action,
+		ref.Collection, ref.ID, ref.Property, ref.TargetCollection, ref.TargetID)), nil
+}
+
+func parseReferenceSpec(req mcp.CallToolRequest) (ReferenceSpec, error) {
+	var (
+		ref ReferenceSpec
+		err error
+	)
+	if ref.Collection, err = req.RequireString("collection"); err != nil {
+		return ref, err
+	}
+	if ref.ID, err = req.RequireString("id"); err != nil {
+		return ref, err
+	}
+	if ref.Property, err = req.RequireString("property"); err != nil {
+		return ref, err
+	}
+	if ref.TargetCollection, err = req.RequireString("targetCollection"); err != nil {
+		return ref, err
+	}
+	if ref.TargetID, err = req.RequireString("targetId"); err != nil {
+		return ref, err
+	}
+	ref.Tenant = req.GetString("tenant", "")
+	if ref.ConsistencyLevel, err = parseConsistencyLevel(req); err != nil {
+		return ref, err
+	}
+	if _, err := uuid.Parse(ref.ID); err != nil {
+		return ref, fmt.Errorf("'id' is not a valid UUID: %s", ref.ID)
+	}
+	if _, err := uuid.Parse(ref.TargetID); err != nil {
+		return ref, fmt.Errorf("'targetId' is not a valid UUID: %s", ref.TargetID)
+	}
+	return ref, nil
+}
+
+// validateReference checks ref against the source collection's schema. It
+// returns a non-empty message when the reference is not allowed by the schema,
+// and an error only when the schema itself could not be fetched.
+func (s *MCPServer) validateReference(ctx context.Context, conn *WeaviateConnection, ref ReferenceSpec) (string, error) {
+	classSchema, err := conn.GetClassSchema(ctx, ref.Collection)
+	if err != nil {
+		return "", err
+	}
+	for _, prop := range classSchema.Properties {
+		if prop.Name != ref.Property {
+			continue
+		}
+		targets := referenceTargets(prop)
+		if len(targets) == 0 {
+			return fmt.Sprintf("property '%s' of collection '%s' is not a cross-reference", ref.Property, ref.Collection), nil
+		}
+		for _, target := range targets {
+			if target == ref.TargetCollection {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("property '%s' of collection '%s' cannot reference '%s' (targets: %s)",
+			ref.Property, ref.Collection, ref.TargetCollection, strings.Join(targets, ", ")), nil
+	}
+	return fmt.Sprintf("property '%s' does not exist in collection '%s'", ref.Property, ref.Collection), nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// currentConfig returns the configuration in effect, which Reload may replace
+// at any time.
+func (s *MCPServer) currentConfig() *Config {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
+// Reload loads the configuration again and applies it to the running server:
+// connections whose settings changed are reconnected, and tools and resources
+// are registered again, which notifies clients with list_changed. Settings
+// that are only read at startup keep their old values. On error the running
+// configuration is left untouched.
+func (s *MCPServer) Reload(ctx context.Context) error {
+	config, err := ReloadConfig()
+	if err != nil {
+		return err
+	}
+	old := s.currentConfig()
+	s.keepStartupSettings(old, config)
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	changed, err := s.connections.Reload(config, s.logger)
+	if err != nil {
+		return err
+	}
+	for _, name := range changed {
+		s.logger.Info("Reconnected Weaviate connection %s", name)
+	}
+
+	s.configMu.Lock()
+	s.config = config
+	s.configMu.Unlock()
+
+	s.registerTools()
+
+	// Force a new set of resources even if the collections did not change,
+	// since the primary connection may have
+	s.resourcesMu.Lock()
+	s.collections = nil
+	s.resourcesMu.Unlock()
+	if err := s.RefreshResources(ctx); err != nil {
+		s.logger.Warn("Failed to refresh schema resources: %v", err)
+	}
+	return nil
+}
+
+// keepStartupSettings copies the settings that only take effect at startup
+// from old to config, warning about those that were changed.
+func (s *MCPServer) keepStartupSettings(old, config *Config) {
+	keep := func(name string, changed bool) {
+		if changed {
+			s.logger.Warn("Config reload: %s changed, restart the server to apply it", name)
+		}
+	}
+	keep("transport", config.Transport != old.Transport)
+	keep("http_host", config.HTTPHost != old.HTTPHost)
+	keep("http_port", config.HTTPPort != old.HTTPPort)
+	keep("http_tls_cert_file", config.HTTPTLSCertFile != old.HTTPTLSCertFile)
+	keep("http_tls_key_file", config.HTTPTLSKeyFile != old.HTTPTLSKeyFile)
+	keep("http_tls_client_ca_file", config.HTTPTLSClientCAFile != old.HTTPTLSClientCAFile)
+	keep("log_level", config.LogLevel != old.LogLevel)
+	keep("log_output", config.LogOutput != old.LogOutput)
+	keep("schema_refresh_interval", config.SchemaRefreshInterval != old.SchemaRefreshInterval)
+	keep("subscription_poll_interval", config.SubscriptionPollInterval != old.SubscriptionPollInterval)
+	keep("config_watch_interval", config.ConfigWatchInterval != old.ConfigWatchInterval)
+
+	config.Transport = old.Transport
+	config.HTTPHost = old.HTTPHost
+	config.HTTPPort = old.HTTPPort
+	config.HTTPTLSCertFile = old.HTTPTLSCertFile
+	config.HTTPTLSKeyFile = old.HTTPTLSKeyFile
+	config.HTTPTLSClientCAFile = old.HTTPTLSClientCAFile
+	config.LogLevel = old.LogLevel
+	config.LogOutput = old.LogOutput
+	config.SchemaRefreshInterval = old.SchemaRefreshInterval
+	config.SubscriptionPollInterval = old.SubscriptionPollInterval
+	config.ConfigWatchInterval = old.ConfigWatchInterval
+}
+
+// WatchConfig reloads the configuration on SIGHUP, and when the config file
+// changes, checking it every interval, until ctx is done. An interval of zero
+// disables watching the file; SIGHUP still works.
+func (s *MCPServer) WatchConfig(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	path := s.currentConfig().ConfigFile
+	var tick <-chan time.Time
+	var last os.FileInfo
+	if path != "" && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+		last, _ = os.Stat(path)
+	}
+
+	reload := func(reason string) {
+		s.logger.Info("Reloading configuration: %s", reason)
+		if err := s.Reload(ctx); err != nil {
+			s.logger.Error("Config reload failed, keeping the current configuration: %v", err)
+			return
+		}
+		s.logger.Info("Configuration reloaded")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			reload("SIGHUP received")
+		case <-tick:
+			// Editors often replace the file, so it may briefly be missing
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+			last = info
+			reload(path + " changed")
+		}
+	}
+}
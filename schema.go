@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// CollectionSchema is the typed view of a collection served by the
+// weaviate://schema/{collection} resource.
+type CollectionSchema struct {
+	Name             string           `json:"name"`
+	Description      string           `json:"description,omitempty"`
+	Vectorizer       string           `json:"vectorizer,omitempty"`
+	VectorizerConfig interface{}      `json:"vectorizerConfig,omitempty"`
+	VectorIndexType  string           `json:"vectorIndexType,omitempty"`
+	NamedVectors     []NamedVector    `json:"namedVectors,omitempty"`
+	Generative       string           `json:"generative,omitempty"`
+	MultiTenancy     bool             `json:"multiTenancy"`
+	Properties       []PropertySchema `json:"properties"`
+}
+
+// PropertySchema describes a single property. References lists the target
+// collections of cross-reference properties, and NestedProperties the fields
+// of object and object[] properties.
+type PropertySchema struct {
+	Name              string           `json:"name"`
+	DataType          []string         `json:"dataType"`
+	Description       string           `json:"description,omitempty"`
+	Tokenization      string           `json:"tokenization,omitempty"`
+	IndexFilterable   *bool            `json:"indexFilterable,omitempty"`
+	IndexSearchable   *bool            `json:"indexSearchable,omitempty"`
+	IndexRangeFilters *bool            `json:"indexRangeFilters,omitempty"`
+	References        []string         `json:"references,omitempty"`
+	NestedProperties  []PropertySchema `json:"nestedProperties,omitempty"`
+}
+
+func describeCollection(class *models.Class) CollectionSchema {
+	summary := summarizeCollection(class)
+	schema := CollectionSchema{
+		Name:            class.Class,
+		Description:     class.Description,
+		Vectorizer:      summary.Vectorizer,
+		VectorIndexType: class.VectorIndexType,
+		Generative:      summary.Generative,
+		MultiTenancy:    summary.MultiTenancy,
+		Properties:      make([]PropertySchema, 0, len(class.Properties)),
+	}
+	if modules, ok := class.ModuleConfig.(map[string]interface{}); ok && schema.Vectorizer != "" {
+		schema.VectorizerConfig = modules[schema.Vectorizer]
+	}
+	vectors := namedVectors(class)
+	for _, name := range sortedKeys(vectors) {
+		schema.NamedVectors = append(schema.NamedVectors, vectors[name])
+	}
+	for _, prop := range class.Properties {
+		schema.Properties = append(schema.Properties, PropertySchema{
+			Name:              prop.Name,
+			DataType:          prop.DataType,
+			Description:       prop.Description,
+			Tokenization:      prop.Tokenization,
+			IndexFilterable:   prop.IndexFilterable,
+			IndexSearchable:   prop.IndexSearchable,
+			IndexRangeFilters: prop.IndexRangeFilters,
+			References:        referenceTargets(prop),
+			NestedProperties:  describeNestedProperties(prop.NestedProperties),
+		})
+	}
+	return schema
+}
+
+func describeNestedProperties(nested []*models.NestedProperty) []PropertySchema {
+	var props []PropertySchema
+	for _, prop := range nested {
+		props = append(props, PropertySchema{
+			Name:              prop.Name,
+			DataType:          prop.DataType,
+			Description:       prop.Description,
+			Tokenization:      prop.Tokenization,
+			IndexFilterable:   prop.IndexFilterable,
+			IndexSearchable:   prop.IndexSearchable,
+			IndexRangeFilters: prop.IndexRangeFilters,
+			NestedProperties:  describeNestedProperties(prop.NestedProperties),
+		})
+	}
+	return props
+}
+
+// Render returns a human-readable rendering of the schema, one property per
+// line with nested properties indented under their parent.
+func (c CollectionSchema) Render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Collection '%s'", c.Name)
+	if c.Description != "" {
+		fmt.Fprintf(&b, ": %s", c.Description)
+	}
+	b.WriteString("\n")
+	if c.Vectorizer != "" {
+		fmt.Fprintf(&b, "Vectorizer: %s\n", c.Vectorizer)
+	}
+	for _, vector := range c.NamedVectors {
+		fmt.Fprintf(&b, "Named vector: %s (%s)\n", vector.Name, vector.Vectorizer)
+	}
+	if c.Generative != "" {
+		fmt.Fprintf(&b, "Generative: %s\n", c.Generative)
+	}
+	if c.MultiTenancy {
+		b.WriteString("Multi-tenancy: enabled\n")
+	}
+	b.WriteString("Properties:\n")
+	renderProperties(&b, c.Properties, 1)
+	return b.String()
+}
+
+func renderProperties(b *strings.Builder, props []PropertySchema, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, prop := range props {
+		fmt.Fprintf(b, "%s- %s (%s)", indent, prop.Name, strings.Join(prop.DataType, ", "))
+		var flags []string
+		if len(prop.References) > 0 {
+			flags = append(flags, "references "+strings.Join(prop.References, ", "))
+		}
+		if prop.Tokenization != "" {
+			flags = append(flags, "tokenization "+prop.Tokenization)
+		}
+		if prop.IndexFilterable != nil && *prop.IndexFilterable {
+			flags = append(flags, "filterable")
+		}
+		if prop.IndexSearchable != nil && *prop.IndexSearchable {
+			flags = append(flags, "searchable")
+		}
+		if prop.IndexRangeFilters != nil && *prop.IndexRangeFilters {
+			flags = append(flags, "range filters")
+		}
+		if len(flags) > 0 {
+			fmt.Fprintf(b, " [%s]", strings.Join(flags, "; "))
+		}
+		if prop.Description != "" {
+			fmt.Fprintf(b, ": %s", prop.Description)
+		}
+		b.WriteString("\n")
+		renderProperties(b, prop.NestedProperties, depth+1)
+	}
+}
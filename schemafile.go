@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/weaviate/weaviate/entities/models"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaChange is one step of the plan that reconciles a schema file with
+// the live schema.
+type SchemaChange struct {
+	Action     string // "create-collection", "add-property" or "incompatible"
+	Collection string
+	Property   string
+	Detail     string
+
+	class *models.Class
+	prop  *models.Property
+}
+
+const (
+	actionCreateCollection = "create-collection"
+	actionAddProperty      = "add-property"
+	actionIncompatible     = "incompatible"
+)
+
+func (c SchemaChange) String() string {
+	switch c.Action {
+	case actionCreateCollection:
+		return fmt.Sprintf("+ create collection %s", c.Collection)
+	case actionAddProperty:
+		return fmt.Sprintf("+ add property %s.%s (%s)", c.Collection, c.Property, strings.Join(c.prop.DataType, ", "))
+	default:
+		target := c.Collection
+		if c.Property != "" {
+			target += "." + c.Property
+		}
+		return fmt.Sprintf("! %s: %s", target, c.Detail)
+	}
+}
+
+// runSchemaCommand runs the "schema" CLI subcommands and returns the process
+// exit code.
+func runSchemaCommand(args []string, config *Config, logger *Logger) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: mcp-server-weaviate schema <apply|diff> -f <file> [-connection <name>]")
+		fmt.Fprintln(os.Stderr, "       mcp-server-weaviate schema dump [-format yaml|json] [-o <file>] [-connection <name>]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("schema "+args[0], flag.ContinueOnError)
+	file := fs.String("f", "", "Schema file (YAML or JSON)")
+	format := fs.String("format", "yaml", "Dump format (yaml/json)")
+	output := fs.String("o", "", "Write the dump to this file instead of stdout")
+	connection := fs.String("connection", "", "Named connection to use (default: the primary connection)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	switch args[0] {
+	case "apply", "diff":
+		if *file == "" {
+			fmt.Fprintf(os.Stderr, "schema %s: -f <file> is required\n", args[0])
+			return 2
+		}
+	case "dump":
+		if *format != "yaml" && *format != "json" {
+			fmt.Fprintf(os.Stderr, "schema dump: invalid format %q, must be 'yaml' or 'json'\n", *format)
+			return 2
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown schema command: %s\n", args[0])
+		return 2
+	}
+
+	var desired *models.Schema
+	if *file != "" {
+		var err error
+		if desired, err = readSchemaFile(*file); err != nil {
+			fmt.Fprintf(os.Stderr, "read schema file: %v\n", err)
+			return 1
+		}
+	}
+	name := *connection
+	if name == "" {
+		name = config.primaryConnection()
+	}
+	connConfig, ok := config.connectionConfigs()[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown connection: %s\n", name)
+		return 2
+	}
+	conn, err := NewWeaviateConnection(name, connConfig, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	live, err := conn.GetSchema(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	if args[0] == "dump" {
+		dump, err := dumpSchema(live, *format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		if *output == "" {
+			fmt.Print(dump)
+			return 0
+		}
+		if err := os.WriteFile(*output, []byte(dump), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "write dump: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	plan := planSchema(desired, live)
+	printPlan(os.Stdout, plan)
+	if args[0] == "diff" {
+		return 0
+	}
+	return applySchemaPlan(ctx, os.Stdout, conn, plan)
+}
+
+// dumpSchema renders schema as YAML or JSON in the layout read by
+// readSchemaFile, so a dump can be fed back to "schema apply".
+func dumpSchema(schema *models.Schema, format string) (string, error) {
+	j, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	if format == "json" {
+		return string(j) + "\n", nil
+	}
+	// Go through JSON so the models' json tags name the YAML keys
+	var doc interface{}
+	if err := json.Unmarshal(j, &doc); err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	y, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	return string(y), nil
+}
+
+// readSchemaFile reads a schema definition in the format returned by
+// Weaviate's /v1/schema endpoint, {"classes": [...]}, written as YAML or
+// JSON.
+func readSchemaFile(path string) (*models.Schema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Decode through YAML, which also accepts JSON, then re-encode as JSON
+	// so the models' json tags apply.
+	var doc interface{}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	j, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	var schema models.Schema
+	if err := json.Unmarshal(j, &schema); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, class := range schema.Classes {
+		if class.Class == "" {
+			return nil, fmt.Errorf("parse %s: every class needs a 'class' name", path)
+		}
+		for _, prop := range class.Properties {
+			if err := validatePropertyDefinition(prop); err != nil {
+				return nil, fmt.Errorf("parse %s: class %s: %w", path, class.Class, err)
+			}
+		}
+	}
+	return &schema, nil
+}
+
+// planSchema compares the desired schema with the live one. Missing
+// collections are created and missing properties added; anything else that
+// differs cannot be changed in place and is reported as incompatible.
+// Collections that exist only in Weaviate are left alone.
+//
+// Cross-reference properties of new collections are split off into
+// add-property steps that run after every collection has been created, so
+// collections in the file may reference each other in any order.
+func planSchema(desired, live *models.Schema) []SchemaChange {
+	liveClasses := make(map[string]*models.Class)
+	for _, class := range live.Classes {
+		liveClasses[strings.ToLower(class.Class)] = class
+	}
+
+	var creates, adds, incompatible []SchemaChange
+	for _, want := range desired.Classes {
+		have, exists := liveClasses[strings.ToLower(want.Class)]
+		if !exists {
+			class := *want
+			class.Properties = nil
+			for _, prop := range want.Properties {
+				if len(referenceTargets(prop)) > 0 {
+					adds = append(adds, SchemaChange{Action: actionAddProperty, Collection: want.Class, Property: prop.Name, prop: prop})
+				} else {
+					class.Properties = append(class.Properties, prop)
+				}
+			}
+			creates = append(creates, SchemaChange{Action: actionCreateCollection, Collection: want.Class, class: &class})
+			continue
+		}
+
+		for _, detail := range classDifferences(want, have) {
+			incompatible = append(incompatible, SchemaChange{Action: actionIncompatible, Collection: want.Class, Detail: detail})
+		}
+
+		haveProps := make(map[string]*models.Property)
+		for _, prop := range have.Properties {
+			haveProps[strings.ToLower(prop.Name)] = prop
+		}
+		wantProps := make(map[string]bool)
+		for _, prop := range want.Properties {
+			wantProps[strings.ToLower(prop.Name)] = true
+			haveProp, exists := haveProps[strings.ToLower(prop.Name)]
+			if !exists {
+				adds = append(adds, SchemaChange{Action: actionAddProperty, Collection: want.Class, Property: prop.Name, prop: prop})
+				continue
+			}
+			for _, detail := range propertyDifferences(prop, haveProp) {
+				incompatible = append(incompatible, SchemaChange{Action: actionIncompatible, Collection: want.Class, Property: prop.Name, Detail: detail})
+			}
+		}
+		for _, prop := range have.Properties {
+			if !wantProps[strings.ToLower(prop.Name)] {
+				incompatible = append(incompatible, SchemaChange{Action: actionIncompatible, Collection: want.Class, Property: prop.Name,
+					Detail: "exists in Weaviate but not in the file; properties cannot be removed"})
+			}
+		}
+	}
+
+	plan := append(creates, adds...)
+	return append(plan, incompatible...)
+}
+
+// classDifferences lists collection-level settings that differ between the
+// file and Weaviate. Settings left out of the file are not compared, since
+// Weaviate fills in defaults for them.
+func classDifferences(want, have *models.Class) []string {
+	var diffs []string
+	if want.Vectorizer != "" && want.Vectorizer != have.Vectorizer {
+		diffs = append(diffs, fmt.Sprintf("vectorizer is %q in Weaviate, file wants %q", have.Vectorizer, want.Vectorizer))
+	}
+	if want.VectorIndexType != "" && want.VectorIndexType != have.VectorIndexType {
+		diffs = append(diffs, fmt.Sprintf("vectorIndexType is %q in Weaviate, file wants %q", have.VectorIndexType, want.VectorIndexType))
+	}
+	if want.MultiTenancyConfig != nil {
+		haveMT := have.MultiTenancyConfig != nil && have.MultiTenancyConfig.Enabled
+		if want.MultiTenancyConfig.Enabled != haveMT {
+			diffs = append(diffs, fmt.Sprintf("multi-tenancy is %v in Weaviate, file wants %v", haveMT, want.MultiTenancyConfig.Enabled))
+		}
+	}
+	for _, name := range sortedKeys(want.VectorConfig) {
+		if _, ok := have.VectorConfig[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("named vector %q does not exist in Weaviate", name))
+		}
+	}
+	return diffs
+}
+
+// propertyDifferences lists property settings that differ between the file
+// and Weaviate.
+func propertyDifferences(want, have *models.Property) []string {
+	var diffs []string
+	if !slices.Equal(want.DataType, have.DataType) {
+		diffs = append(diffs, fmt.Sprintf("dataType is %v in Weaviate, file wants %v", have.DataType, want.DataType))
+	}
+	if want.Tokenization != "" && want.Tokenization != have.Tokenization {
+		diffs = append(diffs, fmt.Sprintf("tokenization is %q in Weaviate, file wants %q", have.Tokenization, want.Tokenization))
+	}
+	for _, flag := range []struct {
+		name       string
+		want, have *bool
+	}{
+		{"indexFilterable", want.IndexFilterable, have.IndexFilterable},
+		{"indexSearchable", want.IndexSearchable, have.IndexSearchable},
+		{"indexRangeFilters", want.IndexRangeFilters, have.IndexRangeFilters},
+	} {
+		if flag.want != nil && (flag.have == nil || *flag.want != *flag.have) {
+			diffs = append(diffs, fmt.Sprintf("%s differs from Weaviate", flag.name))
+		}
+	}
+	return diffs
+}
+
+func printPlan(w io.Writer, plan []SchemaChange) {
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "Schema is up to date")
+		return
+	}
+	for _, change := range plan {
+		fmt.Fprintln(w, change)
+	}
+}
+
+// applySchemaPlan runs the create and add steps of plan in order and returns
+// the exit code: 0 when everything was applied, 1 when a step failed or the
+// plan contains incompatible changes, which are never applied.
+func applySchemaPlan(ctx context.Context, w io.Writer, conn *WeaviateConnection, plan []SchemaChange) int {
+	code := 0
+	for _, change := range plan {
+		var err error
+		switch change.Action {
+		case actionCreateCollection:
+			err = conn.CreateCollection(ctx, change.class)
+		case actionAddProperty:
+			err = conn.AddProperty(ctx, change.Collection, change.prop)
+		default:
+			code = 1
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w, "failed: %s: %v\n", change, err)
+			return 1
+		}
+		fmt.Fprintf(w, "applied: %s\n", change)
+	}
+	if code != 0 {
+		fmt.Fprintln(w, "Incompatible changes were not applied")
+	}
+	return code
+}
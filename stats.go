@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
+)
+
+const (
+	// sampleObjectCount is how many objects weaviate://sample/{collection}
+	// returns.
+	sampleObjectCount = 5
+	// sampleMaxStringLength is the length, in runes, beyond which string
+	// values in sample objects are truncated.
+	sampleMaxStringLength = 200
+	// statsTopValues is how many of the most frequent values are reported
+	// for text properties.
+	statsTopValues = 5
+)
+
+// CollectionStats is the content of the weaviate://stats/{collection}
+// resource.
+type CollectionStats struct {
+	Collection  string          `json:"collection"`
+	ObjectCount int64           `json:"objectCount"`
+	Properties  []PropertyStats `json:"properties"`
+}
+
+// PropertyStats summarizes the values of one property. Count is the number of
+// values present; NullCount is only reported for single-valued properties,
+// where it is the number of objects without a value.
+type PropertyStats struct {
+	Name       string       `json:"name"`
+	DataType   string       `json:"dataType"`
+	Count      int64        `json:"count"`
+	NullCount  *int64       `json:"nullCount,omitempty"`
+	TopValues  []ValueCount `json:"topValues,omitempty"`
+	Minimum    interface{}  `json:"minimum,omitempty"`
+	Maximum    interface{}  `json:"maximum,omitempty"`
+	Mean       *float64     `json:"mean,omitempty"`
+	TotalTrue  *int64       `json:"totalTrue,omitempty"`
+	TotalFalse *int64       `json:"totalFalse,omitempty"`
+}
+
+// ValueCount is one of the most frequent values of a text property.
+type ValueCount struct {
+	Value  string `json:"value"`
+	Occurs int64  `json:"occurs"`
+}
+
+// statsFields returns the aggregation fields computed for a property data
+// type, or nil for types that cannot be aggregated usefully (references,
+// objects, geo coordinates, blobs, ...).
+func statsFields(dataType string) []graphql.Field {
+	switch strings.TrimSuffix(dataType, "[]") {
+	case "text", "string":
+		return []graphql.Field{
+			{Name: "count"},
+			{Name: fmt.Sprintf("topOccurrences(limit: %d)", statsTopValues), Fields: []graphql.Field{{Name: "value"}, {Name: "occurs"}}},
+		}
+	case "int", "number":
+		return []graphql.Field{{Name: "count"}, {Name: "minimum"}, {Name: "maximum"}, {Name: "mean"}}
+	case "boolean":
+		return []graphql.Field{{Name: "count"}, {Name: "totalTrue"}, {Name: "totalFalse"}}
+	case "date":
+		return []graphql.Field{{Name: "count"}, {Name: "minimum"}, {Name: "maximum"}}
+	default:
+		return nil
+	}
+}
+
+func (s *MCPServer) handleStatsResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := req.Params.URI
+	collection, ok := strings.CutPrefix(uri, "weaviate://stats/")
+	if !ok || collection == "" {
+		return nil, fmt.Errorf("invalid resource URI: %s", uri)
+	}
+	if err := s.checkCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	stats, err := s.collectionStats(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for collection %s: %w", collection, err)
+	}
+	b, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal stats for collection %s: %w", collection, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(b),
+		},
+	}, nil
+}
+
+func (s *MCPServer) collectionStats(ctx context.Context, collection string) (*CollectionStats, error) {
+	conn := s.connections.Primary()
+	classSchema, err := conn.GetClassSchema(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	config := s.currentConfig()
+	classSchema = config.redactClass(classSchema)
+
+	fields := []graphql.Field{{Name: "meta", Fields: []graphql.Field{{Name: "count"}}}}
+	for _, prop := range classSchema.Properties {
+		if len(prop.DataType) != 1 {
+			continue
+		}
+		if propFields := statsFields(prop.DataType[0]); propFields != nil {
+			fields = append(fields, graphql.Field{Name: prop.Name, Fields: propFields})
+		}
+	}
+	group, err := conn.Aggregate(ctx, collection, s.pinnedTenant(classSchema), fields...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate: %w", err)
+	}
+
+	meta, _ := group["meta"].(map[string]interface{})
+	stats := &CollectionStats{
+		Collection:  collection,
+		ObjectCount: int64(number(meta["count"])),
+		Properties:  []PropertyStats{},
+	}
+	for _, prop := range classSchema.Properties {
+		values, ok := group[prop.Name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dataType := prop.DataType[0]
+		propStats := PropertyStats{
+			Name:     prop.Name,
+			DataType: dataType,
+			Count:    int64(number(values["count"])),
+			Minimum:  config.maskValue(collection, prop.Name, values["minimum"]),
+			Maximum:  config.maskValue(collection, prop.Name, values["maximum"]),
+		}
+		if !strings.HasSuffix(dataType, "[]") {
+			nulls := stats.ObjectCount - propStats.Count
+			propStats.NullCount = &nulls
+		}
+		if mean, ok := values["mean"].(float64); ok {
+			propStats.Mean = &mean
+		}
+		if _, ok := values["totalTrue"]; ok {
+			totalTrue, totalFalse := int64(number(values["totalTrue"])), int64(number(values["totalFalse"]))
+			propStats.TotalTrue, propStats.TotalFalse = &totalTrue, &totalFalse
+		}
+		for name, raw := range values {
+			if !strings.HasPrefix(name, "topOccurrences") {
+				continue
+			}
+			occurrences, _ := raw.([]interface{})
+			for _, item := range occurrences {
+				occurrence, _ := item.(map[string]interface{})
+				value, _ := config.maskValue(collection, prop.Name, occurrence["value"]).(string)
+				propStats.TopValues = append(propStats.TopValues, ValueCount{
+					Value:  value,
+					Occurs: int64(number(occurrence["occurs"])),
+				})
+			}
+		}
+		stats.Properties = append(stats.Properties, propStats)
+	}
+	return stats, nil
+}
+
+// statsFingerprint changes whenever the collection's object count changes.
+func (s *MCPServer) statsFingerprint(ctx context.Context, collection string) (string, error) {
+	conn := s.connections.Primary()
+	tenant, err := s.resolveTenant(ctx, conn, collection, "")
+	if err != nil {
+		return "", err
+	}
+	count, err := conn.CountObjects(ctx, collection, tenant)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(count), nil
+}
+
+func (s *MCPServer) handleSampleResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := req.Params.URI
+	path, query, _ := strings.Cut(uri, "?")
+	collection, ok := strings.CutPrefix(path, "weaviate://sample/")
+	if !ok || collection == "" {
+		return nil, fmt.Errorf("invalid resource URI: %s", uri)
+	}
+	level, err := resourceConsistencyLevel(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	conn := s.connections.Primary()
+	tenant, err := s.resolveTenant(ctx, conn, collection, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema for collection %s: %w", collection, err)
+	}
+	objs, err := conn.ListObjects(ctx, collection, tenant, level, sampleObjectCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sample objects for collection %s: %w", collection, err)
+	}
+
+	samples := make([]map[string]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		sample := map[string]interface{}{"id": obj.ID.String()}
+		if props, ok := obj.Properties.(map[string]interface{}); ok {
+			props = s.currentConfig().redactProperties(collection, props)
+			sample["properties"] = truncateStrings(props, sampleMaxStringLength)
+		}
+		samples = append(samples, sample)
+	}
+	b, err := json.MarshalIndent(samples, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal sample objects for collection %s: %w", collection, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(b),
+		},
+	}, nil
+}
+
+// resourceConsistencyLevel reads the optional consistencyLevel parameter from
+// the query of a resource URI.
+func resourceConsistencyLevel(query string) (string, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid resource URI query: %w", err)
+	}
+	level := values.Get("consistencyLevel")
+	if level != "" && !slices.Contains(consistencyLevels, level) {
+		return "", fmt.Errorf("invalid consistencyLevel '%s', must be one of %s", level, strings.Join(consistencyLevels, ", "))
+	}
+	return level, nil
+}
+
+// truncateStrings returns a copy of value in which every string longer than
+// max runes, at any depth, is cut down to max runes followed by an ellipsis.
+func truncateStrings(value interface{}, max int) interface{} {
+	return mapStrings(value, func(s string) string {
+		if runes := []rune(s); len(runes) > max {
+			return string(runes[:max]) + "…"
+		}
+		return s
+	})
+}
+
+// mapStrings returns a copy of value in which fn has been applied to every
+// string, at any depth.
+func mapStrings(value interface{}, fn func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = mapStrings(item, fn)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = mapStrings(item, fn)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// number converts a JSON number to float64, treating anything else as zero.
+func number(v interface{}) float64 {
+	f, _ := v.(float64)
+	return f
+}
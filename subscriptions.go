@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// mcp-go advertises resource subscriptions but has no handler for
+// resources/subscribe or resources/unsubscribe and answers them with "method
+// not found". The transports therefore pass every incoming message through
+// rewriteSubscription, which turns those two requests into a ping carrying the
+// original method in subscriptionMethodField. mcp-go answers the ping with the
+// empty result the spec expects, and onRequestInitialization, which sees the
+// message together with the client session, records the subscription.
+const subscriptionMethodField = "subscriptionMethod"
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// resourceFingerprint returns a value that changes whenever the content of a
+// subscribable resource changes.
+type resourceFingerprint func(ctx context.Context, collection string) (string, error)
+
+// subscriptions tracks which sessions are subscribed to which resource URIs,
+// and the last fingerprint seen for each URI.
+type subscriptions struct {
+	mu           sync.Mutex
+	bySession    map[string]map[string]bool
+	fingerprints map[string]string
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{
+		bySession:    make(map[string]map[string]bool),
+		fingerprints: make(map[string]string),
+	}
+}
+
+func (subs *subscriptions) subscribe(sessionID, uri string) {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	if subs.bySession[sessionID] == nil {
+		subs.bySession[sessionID] = make(map[string]bool)
+	}
+	subs.bySession[sessionID][uri] = true
+}
+
+func (subs *subscriptions) unsubscribe(sessionID, uri string) {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	delete(subs.bySession[sessionID], uri)
+	if len(subs.bySession[sessionID]) == 0 {
+		delete(subs.bySession, sessionID)
+	}
+	subs.forgetUnused()
+}
+
+func (subs *subscriptions) removeSession(sessionID string) {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	delete(subs.bySession, sessionID)
+	subs.forgetUnused()
+}
+
+// forgetUnused drops fingerprints of URIs nobody is subscribed to any more, so
+// a later subscription starts from a fresh baseline. Callers hold mu.
+func (subs *subscriptions) forgetUnused() {
+	for uri := range subs.fingerprints {
+		if len(subs.sessionsFor(uri)) == 0 {
+			delete(subs.fingerprints, uri)
+		}
+	}
+}
+
+// sessionsFor returns the sessions subscribed to uri. Callers hold mu.
+func (subs *subscriptions) sessionsFor(uri string) []string {
+	var sessions []string
+	for sessionID, uris := range subs.bySession {
+		if uris[uri] {
+			sessions = append(sessions, sessionID)
+		}
+	}
+	return sessions
+}
+
+// uris returns every URI with at least one subscriber.
+func (subs *subscriptions) uris() []string {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	seen := make(map[string]bool)
+	for _, uris := range subs.bySession {
+		for uri := range uris {
+			seen[uri] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// update stores the fingerprint of uri and returns the sessions to notify:
+// all subscribers if the fingerprint changed, none if it did not or if this
+// is the first fingerprint taken for uri.
+func (subs *subscriptions) update(uri, fingerprint string) []string {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	previous, seen := subs.fingerprints[uri]
+	subs.fingerprints[uri] = fingerprint
+	if !seen || previous == fingerprint {
+		return nil
+	}
+	return subs.sessionsFor(uri)
+}
+
+// subscriptionHooks registers the hooks that record subscriptions and drop
+// them when a session ends.
+func (s *MCPServer) subscriptionHooks(hooks *server.Hooks) {
+	hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+		raw, ok := message.(json.RawMessage)
+		if !ok {
+			return nil
+		}
+		var req struct {
+			Method string `json:"subscriptionMethod"`
+			Params struct {
+				URI string `json:"uri"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(raw, &req); err != nil || req.Method == "" {
+			return nil
+		}
+		session := server.ClientSessionFromContext(ctx)
+		if session == nil {
+			return fmt.Errorf("%s requires a session", req.Method)
+		}
+		_, collection, ok := s.parseSubscribableURI(req.Params.URI)
+		if !ok {
+			return fmt.Errorf("resource %s does not support subscriptions", req.Params.URI)
+		}
+		switch req.Method {
+		case methodResourcesSubscribe:
+			if err := s.checkCollection(ctx, collection); err != nil {
+				return err
+			}
+			s.subscriptions.subscribe(session.SessionID(), req.Params.URI)
+			s.logger.Info("Session %s subscribed to %s", session.SessionID(), req.Params.URI)
+			// Take the baseline now so the first poll only reports real changes
+			s.pollSubscription(ctx, req.Params.URI)
+		case methodResourcesUnsubscribe:
+			s.subscriptions.unsubscribe(session.SessionID(), req.Params.URI)
+			s.logger.Info("Session %s unsubscribed from %s", session.SessionID(), req.Params.URI)
+		}
+		return nil
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		s.subscriptions.removeSession(session.SessionID())
+	})
+}
+
+// parseSubscribableURI splits a subscribable resource URI into the
+// fingerprint used to detect changes and the collection it refers to.
+func (s *MCPServer) parseSubscribableURI(uri string) (resourceFingerprint, string, bool) {
+	for prefix, fingerprint := range s.subscribable {
+		if collection, ok := strings.CutPrefix(uri, prefix); ok && collection != "" {
+			return fingerprint, collection, true
+		}
+	}
+	return nil, "", false
+}
+
+// schemaFingerprint changes whenever the collection's schema changes.
+func (s *MCPServer) schemaFingerprint(ctx context.Context, collection string) (string, error) {
+	class, err := s.connections.Primary().GetClassSchema(ctx, collection)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(s.currentConfig().redactClass(class))
+	if err != nil {
+		return "", fmt.Errorf("marshal schema: %w", err)
+	}
+	return string(b), nil
+}
+
+// WatchSubscriptions polls every subscribed resource every interval until ctx
+// is done, sending notifications/resources/updated to the subscribers of each
+// resource that changed. An interval of zero disables polling.
+func (s *MCPServer) WatchSubscriptions(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, uri := range s.subscriptions.uris() {
+				s.pollSubscription(ctx, uri)
+			}
+		}
+	}
+}
+
+func (s *MCPServer) pollSubscription(ctx context.Context, uri string) {
+	fingerprint, collection, ok := s.parseSubscribableURI(uri)
+	if !ok {
+		return
+	}
+	// The collection may have been denied by a config reload since
+	if err := s.checkCollection(ctx, collection); err != nil {
+		return
+	}
+	value, err := fingerprint(ctx, collection)
+	if err != nil {
+		// A collection that was dropped is a change too
+		s.logger.Debug("Failed to poll %s: %v", uri, err)
+		value = "error: " + err.Error()
+	}
+	for _, sessionID := range s.subscriptions.update(uri, value) {
+		err := s.server.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated,
+			map[string]any{"uri": uri})
+		if err != nil {
+			s.logger.Warn("Failed to notify session %s about %s: %v", sessionID, uri, err)
+		}
+	}
+}
+
+// rewriteSubscription turns a resources/subscribe or resources/unsubscribe
+// request into a ping that keeps its id and params and carries the original
+// method in subscriptionMethodField. Any other message is returned unchanged.
+func rewriteSubscription(message []byte) []byte {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return message
+	}
+	var method string
+	if err := json.Unmarshal(msg["method"], &method); err != nil {
+		return message
+	}
+	if method != methodResourcesSubscribe && method != methodResourcesUnsubscribe {
+		return message
+	}
+	msg["method"], _ = json.Marshal(string(mcp.MethodPing))
+	msg[subscriptionMethodField], _ = json.Marshal(method)
+	rewritten, err := json.Marshal(msg)
+	if err != nil {
+		return message
+	}
+	return rewritten
+}
+
+// subscriptionReader applies rewriteSubscription to each line of a
+// newline-delimited JSON-RPC stream such as stdio.
+type subscriptionReader struct {
+	src *bufio.Reader
+	buf bytes.Buffer
+}
+
+func newSubscriptionReader(r io.Reader) *subscriptionReader {
+	return &subscriptionReader{src: bufio.NewReader(r)}
+}
+
+func (r *subscriptionReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		line, err := r.src.ReadBytes('\n')
+		if len(line) > 0 {
+			trimmed := bytes.TrimRight(line, "\r\n")
+			r.buf.Write(rewriteSubscription(trimmed))
+			r.buf.Write(line[len(trimmed):])
+		}
+		if err != nil {
+			if r.buf.Len() > 0 {
+				break
+			}
+			return 0, err
+		}
+	}
+	return r.buf.Read(p)
+}
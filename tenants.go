@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+// withTenantOption adds the tenant argument shared by every tool that reads
+// or writes objects.
+func withTenantOption() mcp.ToolOption {
+	return mcp.WithString(
+		"tenant",
+		mcp.Description("Tenant to operate on. Required for multi-tenant collections, must be omitted for all others"),
+	)
+}
+
+// tenantTools returns the tenant management tools. They are not registered
+// when the server is pinned to a tenant, since they operate on every tenant
+// of a collection.
+func (s *MCPServer) tenantTools() []server.ServerTool {
+	if s.currentConfig().Tenant != "" {
+		s.logger.Info("Skipped tenant management tools: server is pinned to tenant %s", s.currentConfig().Tenant)
+		return nil
+	}
+	var tools []server.ServerTool
+
+	list := mcp.NewTool(
+		"weaviate-list-tenants",
+		mcp.WithDescription("List the tenants of a multi-tenant collection with their activity status"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+	)
+	tools = append(tools, s.registerTool(list, s.weaviateListTenants, toolRead)...)
+
+	create := mcp.NewTool(
+		"weaviate-create-tenants",
+		mcp.WithDescription("Create tenants in a multi-tenant collection"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"tenants",
+			mcp.Description("Names of the tenants to create"),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"status",
+			mcp.Description("Activity status of the new tenants (default: ACTIVE)"),
+			mcp.Enum(models.TenantActivityStatusACTIVE, models.TenantActivityStatusINACTIVE),
+		),
+	)
+	tools = append(tools, s.registerTool(create, s.weaviateCreateTenants, toolAdmin)...)
+
+	status := mcp.NewTool(
+		"weaviate-set-tenant-status",
+		mcp.WithDescription("Activate or deactivate tenants of a multi-tenant collection. Inactive tenants keep their data but cannot be read or written"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"tenants",
+			mcp.Description("Names of the tenants to update"),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"status",
+			mcp.Description("New activity status"),
+			mcp.Enum(models.TenantActivityStatusACTIVE, models.TenantActivityStatusINACTIVE),
+			mcp.Required(),
+		),
+	)
+	tools = append(tools, s.registerTool(status, s.weaviateSetTenantStatus, toolAdmin)...)
+
+	del := mcp.NewTool(
+		"weaviate-delete-tenants",
+		mcp.WithDescription("Delete tenants and all of their objects from a multi-tenant collection. This cannot be undone"),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Name of the target collection"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"tenants",
+			mcp.Description("Names of the tenants to delete"),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"confirm",
+			mcp.Description("Repeat the collection name to confirm the deletion"),
+			mcp.Required(),
+		),
+	)
+	tools = append(tools, s.registerTool(del, s.weaviateDeleteTenants, toolAdmin)...)
+
+	return tools
+}
+
+func (s *MCPServer) weaviateListTenants(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	collection, err := req.RequireString("collection")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	s.logger.Debug("ListTenants called: collection=%s", collection)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tenants, err := conn.ListTenants(ctx, collection)
+	if err != nil {
+		s.logger.Error("ListTenants error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to list tenants", err), nil
+	}
+	b, err := json.Marshal(tenants)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal tenants", err), nil
+	}
+	s.logger.Info("ListTenants success: collection=%s, tenants=%d", collection, len(tenants))
+	return mcp.NewToolResultText(string(b)), nil
+}
+
+func (s *MCPServer) weaviateCreateTenants(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	collection, names, err := parseTenantNames(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	status := req.GetString("status", models.TenantActivityStatusACTIVE)
+	s.logger.Debug("CreateTenants called: collection=%s, tenants=%v, status=%s", collection, names, status)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := conn.CreateTenants(ctx, collection, tenantsWithStatus(names, status)...); err != nil {
+		s.logger.Error("CreateTenants error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to create tenants", err), nil
+	}
+	s.logger.Info("CreateTenants success: collection=%s, tenants=%v", collection, names)
+	return mcp.NewToolResultText(fmt.Sprintf("Created tenants %s in %s", strings.Join(names, ", "), collection)), nil
+}
+
+func (s *MCPServer) weaviateSetTenantStatus(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	collection, names, err := parseTenantNames(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	status, err := req.RequireString("status")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if status != models.TenantActivityStatusACTIVE && status != models.TenantActivityStatusINACTIVE {
+		return mcp.NewToolResultError("'status' must be ACTIVE or INACTIVE"), nil
+	}
+	s.logger.Debug("SetTenantStatus called: collection=%s, tenants=%v, status=%s", collection, names, status)
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := conn.UpdateTenants(ctx, collection, tenantsWithStatus(names, status)...); err != nil {
+		s.logger.Error("SetTenantStatus error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to update tenants", err), nil
+	}
+	s.logger.Info("SetTenantStatus success: collection=%s, tenants=%v, status=%s", collection, names, status)
+	return mcp.NewToolResultText(fmt.Sprintf("Set tenants %s in %s to %s", strings.Join(names, ", "), collection, status)), nil
+}
+
+func (s *MCPServer) weaviateDeleteTenants(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	collection, names, err := parseTenantNames(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	s.logger.Debug("DeleteTenants called: collection=%s, tenants=%v", collection, names)
+	if confirm := req.GetString("confirm", ""); confirm != collection {
+		s.logger.Error("DeleteTenants not confirmed: collection=%s, confirm=%s", collection, confirm)
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"deletion not confirmed: 'confirm' must repeat the collection name '%s'", collection)), nil
+	}
+	conn, err := s.connection(req)
+	if err != nil {
+		s.logger.Error("Invalid connection: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := conn.DeleteTenants(ctx, collection, names...); err != nil {
+		s.logger.Error("DeleteTenants error: %v", err)
+		return mcp.NewToolResultErrorFromErr("failed to delete tenants", err), nil
+	}
+	s.logger.Info("DeleteTenants success: collection=%s, tenants=%v", collection, names)
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted tenants %s from %s", strings.Join(names, ", "), collection)), nil
+}
+
+// parseTenantNames reads the collection and tenants arguments shared by the
+// tenant management tools.
+func parseTenantNames(req mcp.CallToolRequest) (string, []string, error) {
+	collection, err := req.RequireString("collection")
+	if err != nil {
+		return "", nil, err
+	}
+	names, err := req.RequireStringSlice("tenants")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(names) == 0 {
+		return "", nil, fmt.Errorf("'tenants' must contain at least one tenant name")
+	}
+	return collection, names, nil
+}
+
+func tenantsWithStatus(names []string, status string) []models.Tenant {
+	tenants := make([]models.Tenant, len(names))
+	for i, name := range names {
+		tenants[i] = models.Tenant{Name: name, ActivityStatus: status}
+	}
+	return tenants
+}
+
+// resolveTenant returns the tenant to use for a call on collection. Without a
+// pinned tenant, the requested one is used as is. With one, requesting any
+// other tenant is an error, and the pinned tenant is applied to multi-tenant
+// collections only, since Weaviate rejects a tenant on all others.
+func (s *MCPServer) resolveTenant(ctx context.Context, conn *WeaviateConnection, collection, requested string) (string, error) {
+	if s.currentConfig().Tenant == "" {
+		return requested, nil
+	}
+	if err := s.checkTenant(requested); err != nil {
+		return "", err
+	}
+	class, err := conn.GetClassSchema(ctx, collection)
+	if err != nil {
+		return "", err
+	}
+	return s.pinnedTenant(class), nil
+}
+
+// checkTenant rejects a requested tenant other than the pinned one.
+func (s *MCPServer) checkTenant(requested string) error {
+	pinned := s.currentConfig().Tenant
+	if pinned != "" && requested != "" && requested != pinned {
+		return fmt.Errorf("this server is pinned to tenant '%s' and cannot access tenant '%s'", pinned, requested)
+	}
+	return nil
+}
+
+// pinnedTenant returns the pinned tenant if class is multi-tenant, and ""
+// otherwise.
+func (s *MCPServer) pinnedTenant(class *models.Class) string {
+	if class.MultiTenancyConfig == nil || !class.MultiTenancyConfig.Enabled {
+		return ""
+	}
+	return s.currentConfig().Tenant
+}
@@ -2,44 +2,116 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/data/replication"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
 )
 
+// weaviateTimeout is the HTTP timeout of requests to Weaviate, the client's
+// default.
+const weaviateTimeout = 60 * time.Second
+
 type WeaviateConnection struct {
 	client *weaviate.Client
+	name   string
+
+	// readOnly makes every mutating call fail with ErrReadOnly.
+	readOnly bool
+
+	// consistencyLevel is used for reads and writes that do not request a
+	// level of their own; empty leaves it to Weaviate.
+	consistencyLevel string
+}
+
+// consistencyLevels are the replication consistency levels Weaviate accepts.
+var consistencyLevels = []string{
+	replication.ConsistencyLevel.ONE,
+	replication.ConsistencyLevel.QUORUM,
+	replication.ConsistencyLevel.ALL,
 }
 
-func NewWeaviateConnection(config *Config, logger *Logger) (*WeaviateConnection, error) {
-	logger.Info("Connecting to Weaviate at %s://%s", config.WeaviateScheme, config.WeaviateHost)
-	client, err := weaviate.NewClient(weaviate.Config{
-		Host:           config.WeaviateHost,
-		Scheme:         config.WeaviateScheme,
+// consistency returns level, or the connection's default when level is empty.
+func (conn *WeaviateConnection) consistency(level string) string {
+	if level != "" {
+		return level
+	}
+	return conn.consistencyLevel
+}
+
+func NewWeaviateConnection(name string, config ConnectionConfig, logger *Logger) (*WeaviateConnection, error) {
+	logger.Info("Connecting to Weaviate %s at %s://%s", name, config.Scheme, config.Host)
+	cfg := weaviate.Config{
+		Host:           config.Host,
+		Scheme:         config.Scheme,
+		Headers:        config.Headers,
 		StartupTimeout: time.Second,
-	})
+	}
+	// The API key is sent as a header, as auth.ApiKey would do, rather than
+	// through an AuthConfig: the client then builds a temporary connection to
+	// look up auth info, and it cannot be combined with the read-only HTTP
+	// client below
+	if config.APIKey != "" {
+		cfg.Headers = maps.Clone(config.Headers)
+		if cfg.Headers == nil {
+			cfg.Headers = make(map[string]string)
+		}
+		cfg.Headers["authorization"] = "Bearer " + config.APIKey
+	}
+	if config.ReadOnly {
+		cfg.ConnectionClient = &http.Client{
+			Timeout:   weaviateTimeout,
+			Transport: readOnlyTransport{next: http.DefaultTransport},
+		}
+	}
+	newConnection := func(client *weaviate.Client) *WeaviateConnection {
+		return &WeaviateConnection{
+			client:           client,
+			name:             name,
+			readOnly:         config.ReadOnly,
+			consistencyLevel: config.ConsistencyLevel,
+		}
+	}
+	client, err := weaviate.NewClient(cfg)
+	if err == nil {
+		logger.Info("Successfully connected to Weaviate %s (read-only=%v)", name, config.ReadOnly)
+		return newConnection(client), nil
+	}
+
+	// Weaviate may simply not be up yet. Start anyway without waiting for
+	// readiness; calls fail until it is, and the schema watcher picks up its
+	// collections once it comes up.
+	logger.Warn("Weaviate %s is not ready, continuing without it: %v", name, err)
+	cfg.StartupTimeout = 0
+	client, err = weaviate.NewClient(cfg)
 	if err != nil {
-		logger.Error("Failed to connect to Weaviate: %v", err)
-		return nil, fmt.Errorf("connect to weaviate: %w", err)
+		logger.Error("Failed to connect to Weaviate %s: %v", name, err)
+		return nil, fmt.Errorf("connect to weaviate %s: %w", name, err)
 	}
-	logger.Info("Successfully connected to Weaviate")
-	return &WeaviateConnection{client}, nil
+	return newConnection(client), nil
 }
 
 func (conn *WeaviateConnection) InsertOne(ctx context.Context,
-	collection string, props interface{},
+	collection string, props interface{}, tenant, consistencyLevel string,
 ) (*models.Object, error) {
 	obj := models.Object{
 		Class:      collection,
 		Properties: props,
+		Tenant:     tenant,
 	}
-	// Use batch to leverage autoschema and gRPC
-	resp, err := conn.batchInsert(ctx, &obj)
+	// Use batch to leverage autoschema and gRPC. A single object fits any
+	// batch size, so no cap is passed
+	resp, err := conn.batchInsert(ctx, consistencyLevel, 0, &obj)
 	if err != nil {
 		return nil, fmt.Errorf("insert one object: %w", err)
 	}
@@ -47,32 +119,138 @@ func (conn *WeaviateConnection) InsertOne(ctx context.Context,
 	return &resp[0].Object, err
 }
 
+// SearchOptions holds the optional parameters shared by hybrid and nearText
+// searches.
+type SearchOptions struct {
+	Limit int
+
+	// Tenant is required for multi-tenant collections and must be empty
+	// for all others.
+	Tenant string
+
+	// ConsistencyLevel overrides the connection's default.
+	ConsistencyLevel string
+
+	// TargetVectors selects the named vectors to search. With a Combination,
+	// the per-vector scores are joined using "sum", "average", "minimum",
+	// "manualWeights" or "relativeScore"; the last two take their vectors and
+	// weights from Weights.
+	TargetVectors []string
+	Combination   string
+	Weights       map[string]float32
+}
+
+// targetVectorNames returns every named vector referenced by the options.
+func (opts SearchOptions) targetVectorNames() []string {
+	names := append([]string(nil), opts.TargetVectors...)
+	for name := range opts.Weights {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// targets builds the multi-target argument, or returns nil when the search
+// uses plain targetVectors or the collection's single vector.
+func (opts SearchOptions) targets() *graphql.MultiTargetArgumentBuilder {
+	targets := &graphql.MultiTargetArgumentBuilder{}
+	switch opts.Combination {
+	case "sum":
+		return targets.Sum(opts.TargetVectors...)
+	case "average":
+		return targets.Average(opts.TargetVectors...)
+	case "minimum":
+		return targets.Minimum(opts.TargetVectors...)
+	case "manualWeights":
+		return targets.ManualWeights(opts.Weights)
+	case "relativeScore":
+		return targets.RelativeScore(opts.Weights)
+	default:
+		return nil
+	}
+}
+
 func (conn *WeaviateConnection) Query(ctx context.Context, collection,
-	query string, targetProps []string, limit int,
-) (string, error) {
+	query string, targetProps []string, opts SearchOptions,
+) (*models.GraphQLResponse, error) {
 	hybrid := graphql.HybridArgumentBuilder{}
 	hybrid.WithQuery(query)
+	if targets := opts.targets(); targets != nil {
+		hybrid.WithTargets(targets)
+	} else if len(opts.TargetVectors) > 0 {
+		hybrid.WithTargetVectors(opts.TargetVectors...)
+	}
 	builder := conn.client.GraphQL().Get().
 		WithClassName(collection).WithHybrid(&hybrid).
-		WithFields(func() []graphql.Field {
-			fields := make([]graphql.Field, len(targetProps))
-			for i, prop := range targetProps {
-				fields[i] = graphql.Field{Name: prop}
-			}
-			return fields
-		}()...)
-	if limit > 0 {
-		builder = builder.WithLimit(limit)
+		WithFields(queryFields(targetProps)...)
+	return conn.get(ctx, builder, opts)
+}
+
+func (conn *WeaviateConnection) NearText(ctx context.Context, collection,
+	query string, targetProps []string, opts SearchOptions,
+) (*models.GraphQLResponse, error) {
+	nearText := conn.client.GraphQL().NearTextArgBuilder().
+		WithConcepts([]string{query})
+	if targets := opts.targets(); targets != nil {
+		nearText.WithTargets(targets)
+	} else if len(opts.TargetVectors) > 0 {
+		nearText.WithTargetVectors(opts.TargetVectors...)
 	}
-	res, err := builder.Do(context.Background())
-	if err != nil {
-		return "", err
+	builder := conn.client.GraphQL().Get().
+		WithClassName(collection).WithNearText(nearText).
+		WithFields(queryFields(targetProps)...)
+	return conn.get(ctx, builder, opts)
+}
+
+func (conn *WeaviateConnection) get(ctx context.Context, builder *graphql.GetBuilder, opts SearchOptions) (*models.GraphQLResponse, error) {
+	if opts.Limit > 0 {
+		builder = builder.WithLimit(opts.Limit)
 	}
-	b, err := json.Marshal(res)
-	if err != nil {
-		return "", fmt.Errorf("unmarshal query response: %w", err)
+	if opts.Tenant != "" {
+		builder = builder.WithTenant(opts.Tenant)
+	}
+	if level := conn.consistency(opts.ConsistencyLevel); level != "" {
+		builder = builder.WithConsistencyLevel(level)
+	}
+	return builder.Do(ctx)
+}
+
+// NamedVector describes one named vector of a collection.
+type NamedVector struct {
+	Name             string      `json:"name"`
+	Vectorizer       string      `json:"vectorizer,omitempty"`
+	VectorizerConfig interface{} `json:"vectorizerConfig,omitempty"`
+	VectorIndexType  string      `json:"vectorIndexType,omitempty"`
+}
+
+// namedVectors returns the named vectors configured on class, keyed by name.
+// Collections using the legacy single vector have none.
+func namedVectors(class *models.Class) map[string]NamedVector {
+	vectors := make(map[string]NamedVector)
+	if class == nil {
+		return vectors
+	}
+	for name, cfg := range class.VectorConfig {
+		vector := NamedVector{Name: name, VectorIndexType: cfg.VectorIndexType}
+		if vectorizer, ok := cfg.Vectorizer.(map[string]interface{}); ok {
+			for module, moduleCfg := range vectorizer {
+				vector.Vectorizer = module
+				vector.VectorizerConfig = moduleCfg
+			}
+		}
+		vectors[name] = vector
+	}
+	return vectors
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
-	return string(b), nil
+	sort.Strings(keys)
+	return keys
 }
 
 func (conn *WeaviateConnection) GetClassSchema(ctx context.Context, className string) (*models.Class, error) {
@@ -83,8 +261,327 @@ func (conn *WeaviateConnection) GetClassSchema(ctx context.Context, className st
 	return class, nil
 }
 
-func (conn *WeaviateConnection) batchInsert(ctx context.Context, objs ...*models.Object) ([]models.ObjectsGetResponse, error) {
-	resp, err := conn.client.Batch().ObjectsBatcher().WithObjects(objs...).Do(ctx)
+// AddReference appends a reference to the target object on the source
+// object's reference property.
+func (conn *WeaviateConnection) AddReference(ctx context.Context, ref ReferenceSpec) error {
+	if err := conn.checkWritable("add reference"); err != nil {
+		return err
+	}
+	err := conn.client.Data().ReferenceCreator().
+		WithClassName(ref.Collection).
+		WithID(ref.ID).
+		WithTenant(ref.Tenant).
+		WithConsistencyLevel(conn.consistency(ref.ConsistencyLevel)).
+		WithReferenceProperty(ref.Property).
+		WithReference(conn.referencePayload(ref)).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("add reference: %w", err)
+	}
+	return nil
+}
+
+// ReplaceReference replaces every reference on the source object's reference
+// property with a single reference to the target object.
+func (conn *WeaviateConnection) ReplaceReference(ctx context.Context, ref ReferenceSpec) error {
+	if err := conn.checkWritable("replace reference"); err != nil {
+		return err
+	}
+	err := conn.client.Data().ReferenceReplacer().
+		WithClassName(ref.Collection).
+		WithID(ref.ID).
+		WithTenant(ref.Tenant).
+		WithConsistencyLevel(conn.consistency(ref.ConsistencyLevel)).
+		WithReferenceProperty(ref.Property).
+		WithReferences(&models.MultipleRef{conn.referencePayload(ref)}).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("replace reference: %w", err)
+	}
+	return nil
+}
+
+// DeleteReference removes the reference to the target object from the source
+// object's reference property.
+func (conn *WeaviateConnection) DeleteReference(ctx context.Context, ref ReferenceSpec) error {
+	if err := conn.checkWritable("delete reference"); err != nil {
+		return err
+	}
+	err := conn.client.Data().ReferenceDeleter().
+		WithClassName(ref.Collection).
+		WithID(ref.ID).
+		WithTenant(ref.Tenant).
+		WithConsistencyLevel(conn.consistency(ref.ConsistencyLevel)).
+		WithReferenceProperty(ref.Property).
+		WithReference(conn.referencePayload(ref)).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("delete reference: %w", err)
+	}
+	return nil
+}
+
+// ReferenceSpec identifies a single cross-reference: the source object and
+// reference property it lives on, and the object it points to.
+type ReferenceSpec struct {
+	Collection       string
+	ID               string
+	Property         string
+	TargetCollection string
+	TargetID         string
+	Tenant           string // both objects must belong to this tenant
+	ConsistencyLevel string // overrides the connection's default
+}
+
+func (conn *WeaviateConnection) referencePayload(ref ReferenceSpec) *models.SingleRef {
+	return conn.client.Data().ReferencePayloadBuilder().
+		WithClassName(ref.TargetCollection).
+		WithID(ref.TargetID).
+		Payload()
+}
+
+// queryFields turns targetProperties into GraphQL fields. Dotted reference
+// paths such as "hasAuthor.Author.name" become nested fields with an inline
+// fragment per referenced class, and paths sharing a prefix are merged so
+// "hasAuthor.Author.name" and "hasAuthor.Author.age" select both properties
+// under a single hasAuthor field.
+func queryFields(targetProps []string) []graphql.Field {
+	var fields []graphql.Field
+	for _, prop := range targetProps {
+		fields = mergeField(fields, strings.Split(prop, "."))
+	}
+	return fields
+}
+
+// mergeField adds a path of the form property(.Class.property)* to fields.
+func mergeField(fields []graphql.Field, path []string) []graphql.Field {
+	idx := fieldIndex(fields, path[0])
+	if idx < 0 {
+		fields = append(fields, graphql.Field{Name: path[0]})
+		idx = len(fields) - 1
+	}
+	if len(path) < 3 {
+		return fields
+	}
+
+	fragment := "... on " + path[1]
+	fragIdx := fieldIndex(fields[idx].Fields, fragment)
+	if fragIdx < 0 {
+		fields[idx].Fields = append(fields[idx].Fields, graphql.Field{Name: fragment})
+		fragIdx = len(fields[idx].Fields) - 1
+	}
+	frag := &fields[idx].Fields[fragIdx]
+	frag.Fields = mergeField(frag.Fields, path[2:])
+	return fields
+}
+
+func fieldIndex(fields []graphql.Field, name string) int {
+	for i, field := range fields {
+		if field.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// referenceTargets returns the classes a cross-reference property points to,
+// or nil for primitive and nested object properties. Weaviate stores reference
+// targets in DataType as class names, which always start with an upper case
+// letter, while primitive types ("text", "int[]", "object") are lower case.
+func referenceTargets(prop *models.Property) []string {
+	var targets []string
+	for _, dt := range prop.DataType {
+		if dt != "" && unicode.IsUpper([]rune(dt)[0]) {
+			targets = append(targets, dt)
+		}
+	}
+	return targets
+}
+
+// CreateCollection creates a collection from its class definition.
+func (conn *WeaviateConnection) CreateCollection(ctx context.Context, class *models.Class) error {
+	if err := conn.checkWritable("create collection"); err != nil {
+		return err
+	}
+	if err := conn.client.Schema().ClassCreator().WithClass(class).Do(ctx); err != nil {
+		return fmt.Errorf("create collection: %w", err)
+	}
+	return nil
+}
+
+// AddProperty adds a property to an existing collection.
+func (conn *WeaviateConnection) AddProperty(ctx context.Context, collection string, prop *models.Property) error {
+	if err := conn.checkWritable("add property"); err != nil {
+		return err
+	}
+	err := conn.client.Schema().PropertyCreator().
+		WithClassName(collection).
+		WithProperty(prop).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("add property: %w", err)
+	}
+	return nil
+}
+
+// DeleteCollection deletes a collection and all of its objects.
+func (conn *WeaviateConnection) DeleteCollection(ctx context.Context, collection string) error {
+	if err := conn.checkWritable("delete collection"); err != nil {
+		return err
+	}
+	if err := conn.client.Schema().ClassDeleter().WithClassName(collection).Do(ctx); err != nil {
+		return fmt.Errorf("delete collection: %w", err)
+	}
+	return nil
+}
+
+// GetSchema returns the schema of every collection.
+func (conn *WeaviateConnection) GetSchema(ctx context.Context) (*models.Schema, error) {
+	dump, err := conn.client.Schema().Getter().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get schema: %w", err)
+	}
+	return &dump.Schema, nil
+}
+
+// CountObjects returns the number of objects in a collection.
+func (conn *WeaviateConnection) CountObjects(ctx context.Context, collection, tenant string) (int64, error) {
+	group, err := conn.Aggregate(ctx, collection, tenant,
+		graphql.Field{Name: "meta", Fields: []graphql.Field{{Name: "count"}}})
+	if err != nil {
+		return 0, fmt.Errorf("count objects: %w", err)
+	}
+	meta, _ := group["meta"].(map[string]interface{})
+	count, _ := meta["count"].(float64)
+	return int64(count), nil
+}
+
+// Aggregate runs an ungrouped aggregation over a collection and returns its
+// single result, keyed by field name. tenant is required for multi-tenant
+// collections and must be empty for all others. Weaviate aggregations take
+// no consistency level: they are computed from one replica of each shard.
+func (conn *WeaviateConnection) Aggregate(ctx context.Context, collection, tenant string,
+	fields ...graphql.Field,
+) (map[string]interface{}, error) {
+	builder := conn.client.GraphQL().Aggregate().
+		WithClassName(collection).
+		WithFields(fields...)
+	if tenant != "" {
+		builder = builder.WithTenant(tenant)
+	}
+	res, err := builder.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := graphQLError(res); err != nil {
+		return nil, err
+	}
+	aggregate, _ := res.Data["Aggregate"].(map[string]interface{})
+	groups, _ := aggregate[collection].([]interface{})
+	if len(groups) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	group, _ := groups[0].(map[string]interface{})
+	return group, nil
+}
+
+// ListObjects returns up to limit objects of a collection, without vectors.
+// An empty consistencyLevel uses the connection's default.
+func (conn *WeaviateConnection) ListObjects(ctx context.Context, collection, tenant, consistencyLevel string, limit int) ([]*models.Object, error) {
+	getter := conn.client.Data().ObjectsGetter().
+		WithClassName(collection).
+		WithTenant(tenant).
+		WithLimit(limit)
+	if level := conn.consistency(consistencyLevel); level != "" {
+		getter = getter.WithConsistencyLevel(level)
+	}
+	objs, err := getter.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list objects: %w", err)
+	}
+	return objs, nil
+}
+
+// ListTenants returns the tenants of a multi-tenant collection.
+func (conn *WeaviateConnection) ListTenants(ctx context.Context, collection string) ([]models.Tenant, error) {
+	tenants, err := conn.client.Schema().TenantsGetter().WithClassName(collection).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+// CreateTenants adds tenants to a multi-tenant collection.
+func (conn *WeaviateConnection) CreateTenants(ctx context.Context, collection string, tenants ...models.Tenant) error {
+	if err := conn.checkWritable("create tenants"); err != nil {
+		return err
+	}
+	err := conn.client.Schema().TenantsCreator().
+		WithClassName(collection).
+		WithTenants(tenants...).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("create tenants: %w", err)
+	}
+	return nil
+}
+
+// UpdateTenants changes the activity status of existing tenants.
+func (conn *WeaviateConnection) UpdateTenants(ctx context.Context, collection string, tenants ...models.Tenant) error {
+	if err := conn.checkWritable("update tenants"); err != nil {
+		return err
+	}
+	err := conn.client.Schema().TenantsUpdater().
+		WithClassName(collection).
+		WithTenants(tenants...).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("update tenants: %w", err)
+	}
+	return nil
+}
+
+// DeleteTenants removes tenants, and all of their objects, from a
+// multi-tenant collection.
+func (conn *WeaviateConnection) DeleteTenants(ctx context.Context, collection string, tenants ...string) error {
+	if err := conn.checkWritable("delete tenants"); err != nil {
+		return err
+	}
+	err := conn.client.Schema().TenantsDeleter().
+		WithClassName(collection).
+		WithTenants(tenants...).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("delete tenants: %w", err)
+	}
+	return nil
+}
+
+// graphQLError joins the errors of a GraphQL response, which Weaviate returns
+// alongside a successful HTTP status.
+func graphQLError(res *models.GraphQLResponse) error {
+	var err error
+	for _, gqlErr := range res.Errors {
+		err = errors.Join(err, errors.New(gqlErr.Message))
+	}
+	return err
+}
+
+// batchInsert inserts objs in one batch request. maxBatchSize, the
+// MaxBatchSize limit of the calling tool, caps the number of objects; zero
+// means no cap.
+func (conn *WeaviateConnection) batchInsert(ctx context.Context, consistencyLevel string, maxBatchSize int, objs ...*models.Object) ([]models.ObjectsGetResponse, error) {
+	if err := conn.checkWritable("insert objects"); err != nil {
+		return nil, err
+	}
+	if maxBatchSize > 0 && len(objs) > maxBatchSize {
+		return nil, fmt.Errorf("batch of %d objects exceeds the maximum batch size of %d; split the batch", len(objs), maxBatchSize)
+	}
+	batcher := conn.client.Batch().ObjectsBatcher().WithObjects(objs...)
+	if level := conn.consistency(consistencyLevel); level != "" {
+		batcher = batcher.WithConsistencyLevel(level)
+	}
+	resp, err := batcher.Do(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("make insertion request: %w", err)
 	}